@@ -0,0 +1,84 @@
+package superchain
+
+import (
+	"fmt"
+	"math"
+)
+
+// BlockNumberAt returns the number of the L2 block of the chain at the timestamp:
+// the latest block with a timestamp at or before it.
+func BlockNumberAt(chainID uint64, timestamp uint64) (uint64, error) {
+	return blockNumberAt(OPChains, chainID, timestamp)
+}
+
+// BlockNumberAt is like the package-level BlockNumberAt, but uses the chains of the registry.
+func (r *Registry) BlockNumberAt(chainID uint64, timestamp uint64) (uint64, error) {
+	return blockNumberAt(r.OPChains, chainID, timestamp)
+}
+
+func blockNumberAt(chains map[uint64]*ChainConfig, chainID uint64, timestamp uint64) (uint64, error) {
+	chain, err := chainByID(chains, chainID)
+	if err != nil {
+		return 0, err
+	}
+	return chain.BlockNumberAt(timestamp)
+}
+
+// TimestampOf returns the timestamp of the L2 block of the chain with the given number.
+func TimestampOf(chainID uint64, number uint64) (uint64, error) {
+	return timestampOf(OPChains, chainID, number)
+}
+
+// TimestampOf is like the package-level TimestampOf, but uses the chains of the registry.
+func (r *Registry) TimestampOf(chainID uint64, number uint64) (uint64, error) {
+	return timestampOf(r.OPChains, chainID, number)
+}
+
+func timestampOf(chains map[uint64]*ChainConfig, chainID uint64, number uint64) (uint64, error) {
+	chain, err := chainByID(chains, chainID)
+	if err != nil {
+		return 0, err
+	}
+	return chain.TimestampOf(number)
+}
+
+// BlockNumberAt returns the number of the latest L2 block with a timestamp at or before the given timestamp.
+// Timestamps before the L2 genesis have no block.
+func (c *ChainConfig) BlockNumberAt(timestamp uint64) (uint64, error) {
+	if timestamp < c.Genesis.L2Time {
+		return 0, fmt.Errorf("timestamp %d is before the L2 genesis of chain %d at %d", timestamp, c.ChainID, c.Genesis.L2Time)
+	}
+	blockTime, err := c.blockTime()
+	if err != nil {
+		return 0, err
+	}
+	blocks := (timestamp - c.Genesis.L2Time) / blockTime
+	if blocks > math.MaxUint64-c.Genesis.L2.Number {
+		return 0, fmt.Errorf("block number at timestamp %d of chain %d overflows", timestamp, c.ChainID)
+	}
+	return c.Genesis.L2.Number + blocks, nil
+}
+
+// TimestampOf returns the timestamp of the L2 block with the given number.
+// Blocks before the L2 genesis, e.g. the legacy blocks of chains migrated to Bedrock, are not supported.
+func (c *ChainConfig) TimestampOf(number uint64) (uint64, error) {
+	if number < c.Genesis.L2.Number {
+		return 0, fmt.Errorf("block %d is before the L2 genesis of chain %d at block %d", number, c.ChainID, c.Genesis.L2.Number)
+	}
+	blockTime, err := c.blockTime()
+	if err != nil {
+		return 0, err
+	}
+	blocks := number - c.Genesis.L2.Number
+	if blocks > (math.MaxUint64-c.Genesis.L2Time)/blockTime {
+		return 0, fmt.Errorf("timestamp of block %d of chain %d overflows", number, c.ChainID)
+	}
+	return c.Genesis.L2Time + blocks*blockTime, nil
+}
+
+func (c *ChainConfig) blockTime() (uint64, error) {
+	if c.BlockTime == nil || *c.BlockTime == 0 {
+		return 0, fmt.Errorf("chain %d has no block time", c.ChainID)
+	}
+	return *c.BlockTime, nil
+}
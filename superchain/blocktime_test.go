@@ -0,0 +1,84 @@
+package superchain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"testing/quick"
+)
+
+// TestBlockNumberAt checks the conversions between L2 timestamps and block numbers of every chain.
+func TestBlockNumberAt(t *testing.T) {
+	for id, chain := range OPChains {
+		genesis := chain.Genesis
+		blockTime := *chain.BlockTime
+
+		// A block is at its own timestamp, and at all timestamps until the next block.
+		roundTrip := func(offset uint32) bool {
+			number := genesis.L2.Number + uint64(offset)
+			ts, err := TimestampOf(id, number)
+			if err != nil {
+				return false
+			}
+			for i := uint64(0); i < blockTime; i++ {
+				if n, err := BlockNumberAt(id, ts+i); err != nil || n != number {
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(roundTrip, nil); err != nil {
+			t.Errorf("chain %d: %v", id, err)
+		}
+
+		// The block at a timestamp is the latest block at or before it.
+		latest := func(offset uint32) bool {
+			ts := genesis.L2Time + uint64(offset)
+			number, err := BlockNumberAt(id, ts)
+			if err != nil {
+				return false
+			}
+			blockTs, err := TimestampOf(id, number)
+			return err == nil && blockTs <= ts && ts < blockTs+blockTime
+		}
+		if err := quick.Check(latest, nil); err != nil {
+			t.Errorf("chain %d: %v", id, err)
+		}
+
+		if n, err := BlockNumberAt(id, genesis.L2Time); err != nil || n != genesis.L2.Number {
+			t.Errorf("chain %d: genesis time is not at the genesis block: %d, %v", id, n, err)
+		}
+		if _, err := BlockNumberAt(id, genesis.L2Time-1); err == nil {
+			t.Errorf("chain %d: expected error for timestamp before genesis", id)
+		}
+		if genesis.L2.Number > 0 {
+			if _, err := TimestampOf(id, genesis.L2.Number-1); err == nil {
+				t.Errorf("chain %d: expected error for block before genesis", id)
+			}
+		}
+		if _, err := TimestampOf(id, math.MaxUint64); err == nil {
+			t.Errorf("chain %d: expected overflow error", id)
+		}
+	}
+	if _, err := BlockNumberAt(4242, 0); !errors.Is(err, ErrUnknownChain) {
+		t.Errorf("expected ErrUnknownChain, got %v", err)
+	}
+}
+
+func TestRegistryBlockNumberAt(t *testing.T) {
+	overlay := privateDevnet(t, "4242")
+	overlay["configs/sepolia/private-devnet.yaml"].Data = []byte("name: Private Devnet\nchain_id: 4242\ngenesis:\n  l2:\n    number: 10\n  l2_time: 1000\n")
+	reg, err := LoadOverlay(embeddedFS, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := reg.BlockNumberAt(4242, 1005); err != nil || n != 12 {
+		t.Errorf("expected block 12 at timestamp 1005, got %d, %v", n, err)
+	}
+	if ts, err := reg.TimestampOf(4242, 12); err != nil || ts != 1004 {
+		t.Errorf("expected timestamp 1004 of block 12, got %d, %v", ts, err)
+	}
+	if _, err := TimestampOf(4242, 12); !errors.Is(err, ErrUnknownChain) {
+		t.Errorf("registry chain should not be part of the package-level chains: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package superchain
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ForkActivation is the activation of a fork on a chain.
+type ForkActivation struct {
+	Superchain string `json:"superchain"`
+	ChainID    uint64 `json:"chainId"`
+	ChainName  string `json:"chainName"`
+	Fork       Fork   `json:"fork"`
+	// Time is the activation timestamp, UTC is the same time as a date.
+	Time uint64    `json:"time"`
+	UTC  time.Time `json:"utc"`
+	// L2Block is the first L2 block with the fork active.
+	// Forks that activate before the L2 genesis are active from the genesis block.
+	L2Block uint64 `json:"l2Block"`
+	// Active is whether the fork is active at the time of the report.
+	Active bool `json:"active"`
+	// RemainingSeconds is the time until the activation, or 0 if the fork is active.
+	RemainingSeconds uint64 `json:"remainingSeconds"`
+}
+
+// ForkCalendar returns every scheduled fork of every chain, as of now,
+// ordered by superchain target, chain ID and fork.
+func ForkCalendar(now time.Time) []ForkActivation {
+	return forkCalendar(Superchains, OPChains, now)
+}
+
+// ForkCalendar is like the package-level ForkCalendar, but uses the chains of the registry.
+func (r *Registry) ForkCalendar(now time.Time) []ForkActivation {
+	return forkCalendar(r.Superchains, r.OPChains, now)
+}
+
+func forkCalendar(superchains map[string]*Superchain, chains map[uint64]*ChainConfig, now time.Time) []ForkActivation {
+	var out []ForkActivation
+	for _, chain := range chains {
+		if _, ok := superchains[chain.Superchain]; !ok {
+			continue
+		}
+		for _, fork := range Forks {
+			t, ok := chain.Hardforks[fork]
+			if !ok {
+				continue
+			}
+			activation := ForkActivation{
+				Superchain: chain.Superchain,
+				ChainID:    chain.ChainID,
+				ChainName:  chain.Name,
+				Fork:       fork,
+				Time:       t,
+				UTC:        time.Unix(int64(t), 0).UTC(),
+				L2Block:    chain.firstBlockAt(t),
+				Active:     uint64(now.Unix()) >= t,
+			}
+			if !activation.Active {
+				activation.RemainingSeconds = t - uint64(now.Unix())
+			}
+			out = append(out, activation)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := &out[i], &out[j]
+		if a.Superchain != b.Superchain {
+			return a.Superchain < b.Superchain
+		}
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		return a.Fork.index() < b.Fork.index()
+	})
+	return out
+}
+
+// Upcoming returns the activations that are not active yet, ordered by activation time.
+func Upcoming(activations []ForkActivation) []ForkActivation {
+	var out []ForkActivation
+	for _, a := range activations {
+		if !a.Active {
+			out = append(out, a)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time < out[j].Time })
+	return out
+}
+
+// firstBlockAt returns the number of the first L2 block with a timestamp at or after t.
+// Before the L2 genesis, or if the chain has no block time, that is the genesis block.
+func (c *ChainConfig) firstBlockAt(t uint64) uint64 {
+	number, err := c.BlockNumberAt(t)
+	if err != nil {
+		return c.Genesis.L2.Number
+	}
+	if ts, err := c.TimestampOf(number); err == nil && ts < t {
+		number++
+	}
+	return number
+}
+
+// WriteICalendar writes the activations as an iCalendar (RFC 5545) feed, with an event per activation.
+// Events only have a start time: an event without an end is a point in time.
+// Activations at time 0, forks that are active from genesis, are not a scheduled upgrade, and are skipped.
+// The stamp is the creation time of the feed.
+func WriteICalendar(w io.Writer, activations []ForkActivation, stamp time.Time) error {
+	const layout = "20060102T150405Z"
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//ethereum-optimism//superchain-registry//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Superchain upgrades",
+	}
+	for _, a := range activations {
+		if a.Time == 0 {
+			continue
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-%d@superchain-registry", a.Fork, a.ChainID),
+			"DTSTAMP:"+stamp.UTC().Format(layout),
+			"DTSTART:"+a.UTC.Format(layout),
+			"SUMMARY:"+icalText(fmt.Sprintf("%s activation on %s (%d)", a.Fork.displayName(), a.ChainName, a.ChainID)),
+			"DESCRIPTION:"+icalText(fmt.Sprintf("The %s fork activates on %s (chain %d, superchain %s) at timestamp %d, in L2 block %d.",
+				a.Fork, a.ChainName, a.ChainID, a.Superchain, a.Time, a.L2Block)),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+	for _, line := range lines {
+		if _, err := io.WriteString(w, icalFold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// icalText escapes a text value of an iCalendar property.
+func icalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icalFold folds a content line into lines of at most 75 octets, as required by iCalendar.
+// Continuation lines start with a space.
+func icalFold(line string) string {
+	const limit = 75
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package superchain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForkCalendar(t *testing.T) {
+	now := time.Unix(1704992401-60, 0) // a minute before Canyon on mainnet
+	calendar := ForkCalendar(now)
+
+	var canyon *ForkActivation
+	for i := range calendar {
+		if a := &calendar[i]; a.ChainID == 10 && a.Fork == Canyon {
+			canyon = a
+		}
+	}
+	if canyon == nil {
+		t.Fatal("missing Canyon activation of OP Mainnet")
+	}
+	if canyon.L2Block != 114696812 {
+		t.Errorf("unexpected Canyon block %d", canyon.L2Block)
+	}
+	if canyon.Active || canyon.RemainingSeconds != 60 || canyon.UTC != time.Date(2024, 1, 11, 17, 0, 1, 0, time.UTC) {
+		t.Errorf("unexpected Canyon activation %+v", canyon)
+	}
+
+	upcoming := Upcoming(calendar)
+	for i, a := range upcoming {
+		if a.Active {
+			t.Errorf("active fork %s of chain %d is upcoming", a.Fork, a.ChainID)
+		}
+		if i > 0 && upcoming[i-1].Time > a.Time {
+			t.Error("upcoming forks are not ordered by time")
+		}
+	}
+	if len(upcoming) == 0 || len(upcoming) == len(calendar) {
+		t.Errorf("unexpected number of upcoming forks %d of %d", len(upcoming), len(calendar))
+	}
+}
+
+func TestFirstBlockAt(t *testing.T) {
+	chain := &ChainConfig{
+		Genesis:          ChainGenesis{L2: BlockID{Number: 100}, L2Time: 1000},
+		RollupParameters: RollupParameters{BlockTime: u64ptr(2)},
+	}
+	for at, expected := range map[uint64]uint64{0: 100, 1000: 100, 1001: 101, 1002: 101, 1003: 102} {
+		if got := chain.firstBlockAt(at); got != expected {
+			t.Errorf("first block at %d: got %d, expected %d", at, got, expected)
+		}
+	}
+}
+
+func TestWriteICalendar(t *testing.T) {
+	var buf bytes.Buffer
+	calendar := ForkCalendar(time.Unix(0, 0))
+	if err := WriteICalendar(&buf, calendar, time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Fatal("not an iCalendar feed")
+	}
+	scheduled := 0
+	for _, a := range calendar {
+		if a.Time != 0 {
+			scheduled++
+		}
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT\r\n"); n != scheduled {
+		t.Errorf("got %d events, expected %d", n, scheduled)
+	}
+	if strings.Contains(out, "DTEND:") || strings.Contains(out, "DTSTART:19700101") {
+		t.Error("events should have no end, and no activations at time 0")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line is not folded: %q", line)
+		}
+	}
+	if !strings.Contains(out, "UID:canyon-10@superchain-registry\r\nDTSTAMP:19700101T000000Z\r\nDTSTART:20240111T170001Z\r\n") {
+		t.Error("missing Canyon event of OP Mainnet")
+	}
+	if unfolded := strings.ReplaceAll(out, "\r\n ", ""); !strings.Contains(unfolded, `chain 10\, superchain mainnet`) {
+		t.Error("commas in text are not escaped")
+	}
+}
@@ -0,0 +1,91 @@
+// Command check-security-configs checks the security configs of the L1 contracts
+// of all chains of a superchain target, like scripts/CheckSecurityConfigs.s.sol.
+// It also checks that all proxies point at implementations known to the registry.
+//
+// Usage:
+//
+//	check-security-configs [-superchain mainnet] [-rpc https://...] [-v]
+//
+// The L1 RPC defaults to the public RPC of the superchain target.
+// The command exits with a non-zero status if any check fails.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+	"github.com/ethereum-optimism/superchain-registry/superchain/verify"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	target := flag.String("superchain", "mainnet", "superchain target to check the chains of")
+	rpcURL := flag.String("rpc", "", "L1 RPC endpoint, defaults to the public RPC of the superchain target")
+	verbose := flag.Bool("v", false, "print passed checks too")
+	flag.Parse()
+
+	sc, err := superchain.SuperchainByName(*target)
+	if err != nil {
+		return err
+	}
+	client, err := verify.NewL1Client(sc.Config.L1)
+	if *rpcURL != "" {
+		client, err = verify.NewClient(*rpcURL), nil
+	}
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if id, err := client.ChainID(ctx); err != nil {
+		return fmt.Errorf("failed to get L1 chain ID: %w", err)
+	} else if id != sc.Config.L1.ChainID {
+		return fmt.Errorf("RPC serves chain %d, but superchain target %s is on L1 chain %d", id, *target, sc.Config.L1.ChainID)
+	}
+
+	chainIDs := append([]uint64{}, sc.ChainIDs...)
+	sort.Slice(chainIDs, func(i, j int) bool { return chainIDs[i] < chainIDs[j] })
+	impls, err := superchain.ImplementationsFor(*target)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, chainID := range chainIDs {
+		fmt.Printf("Checking %s (%d)\n", superchain.OPChains[chainID].Name, chainID)
+		report, err := verify.CheckSecurityConfigs(ctx, client, chainID, superchain.Addresses[chainID])
+		if err != nil {
+			return err
+		}
+		for i := range report.Checks {
+			if c := &report.Checks[i]; *verbose || !c.OK() {
+				fmt.Printf("  %s\n", c)
+			}
+		}
+		if err := report.Err(); err != nil {
+			errs = append(errs, err)
+		}
+		proxies, err := verify.CheckProxyImplementations(ctx, client, chainID, superchain.Addresses[chainID], impls)
+		if err != nil {
+			return err
+		}
+		for i := range proxies.Proxies {
+			if p := &proxies.Proxies[i]; *verbose || !p.Known() {
+				fmt.Printf("  %s\n", p)
+			}
+		}
+		if err := proxies.Err(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
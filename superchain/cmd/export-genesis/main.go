@@ -0,0 +1,50 @@
+// Command export-genesis writes the complete genesis.json of a registered chain,
+// with all bytecode inlined and the op-geth chain config included,
+// to initialize op-geth with.
+//
+// Usage:
+//
+//	export-genesis -chain-id 10 [-out genesis.json]
+//
+// The genesis is written to stdout if no output file is given.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	chainID := flag.Uint64("chain-id", 0, "chain ID of the chain to export the genesis of")
+	out := flag.String("out", "", "output file, defaults to stdout")
+	flag.Parse()
+
+	if _, err := superchain.ChainByID(*chainID); err != nil {
+		return err
+	}
+	genesis, err := superchain.ExportGenesis(*chainID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0o644)
+}
@@ -0,0 +1,61 @@
+// Command fork-calendar reports the fork activations of all chains:
+// the activation time, the L2 block of the activation, and the time remaining.
+//
+// Usage:
+//
+//	fork-calendar [-upcoming] [-format text|json|ics]
+//
+// The ics format is an iCalendar feed, with an event per activation.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	upcoming := flag.Bool("upcoming", false, "only report forks that are not active yet, ordered by activation time")
+	format := flag.String("format", "text", "output format: text, json or ics")
+	flag.Parse()
+
+	now := time.Now()
+	activations := superchain.ForkCalendar(now)
+	if *upcoming {
+		activations = superchain.Upcoming(activations)
+	}
+	switch *format {
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SUPERCHAIN\tCHAIN\tCHAIN ID\tFORK\tUTC\tL2 BLOCK\tREMAINING")
+		for _, a := range activations {
+			remaining := "active"
+			if !a.Active {
+				remaining = (time.Duration(a.RemainingSeconds) * time.Second).String()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%d\t%s\n",
+				a.Superchain, a.ChainName, a.ChainID, a.Fork, a.UTC.Format(time.RFC3339), a.L2Block, remaining)
+		}
+		return w.Flush()
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(activations)
+	case "ics":
+		return superchain.WriteICalendar(os.Stdout, activations, now)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
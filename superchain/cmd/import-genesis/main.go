@@ -0,0 +1,54 @@
+// Command import-genesis compacts a full genesis.json into the registry format:
+// the genesis with code hashes instead of code, in extra/genesis/<superchain>/<chain>.json.gz,
+// and the deduplicated bytecode, in extra/bytecodes/<hash>.bin.gz.
+//
+// Usage:
+//
+//	import-genesis -genesis genesis.json -superchain sepolia -chain op [-dir superchain]
+//
+// The registry directory defaults to the current directory.
+// The output is deterministic, and verified to load back to the imported genesis.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	genesisPath := flag.String("genesis", "", "full genesis.json to import")
+	target := flag.String("superchain", "", "superchain target of the chain")
+	chain := flag.String("chain", "", "name of the chain within its superchain target, e.g. op")
+	dir := flag.String("dir", ".", "registry directory, the superchain module directory")
+	flag.Parse()
+
+	if *genesisPath == "" || *target == "" || *chain == "" {
+		return errors.New("the -genesis, -superchain and -chain flags are required")
+	}
+	data, err := os.ReadFile(*genesisPath)
+	if err != nil {
+		return err
+	}
+	var full superchain.GethGenesis
+	if err := json.Unmarshal(data, &full); err != nil {
+		return fmt.Errorf("failed to decode genesis: %w", err)
+	}
+	genesis, err := superchain.ImportGenesis(*dir, *target, *chain, &full)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported genesis of %s/%s with %d accounts\n", *target, *chain, len(genesis.Alloc))
+	return nil
+}
@@ -0,0 +1,45 @@
+// Command rollup-config writes the op-node rollup.json of a registered chain.
+//
+// Usage:
+//
+//	rollup-config -chain-id 10 [-out rollup.json]
+//
+// The rollup config is written to stdout if no output file is given.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	chainID := flag.Uint64("chain-id", 0, "chain ID of the chain to write the rollup config of")
+	out := flag.String("out", "", "output file, defaults to stdout")
+	flag.Parse()
+
+	cfg, err := superchain.LoadRollupConfig(*chainID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0o644)
+}
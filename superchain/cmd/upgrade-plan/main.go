@@ -0,0 +1,94 @@
+// Command upgrade-plan prints the plan to upgrade the L1 contracts of a chain
+// from its deployed versions to the versions of the superchain semver.yaml.
+//
+// Usage:
+//
+//	upgrade-plan -chain-id 10 -deployed deployed.yaml [-format safe] [-init init.json]
+//
+// The deployed versions file uses the same format as semver.yaml.
+// With -format safe, the plan is printed as a Safe Transaction Builder batch of ProxyAdmin calls.
+// The optional init file is a JSON object of contract names to hex-encoded calldata,
+// which is called on the proxy after upgrading it.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	chainID := flag.Uint64("chain-id", 0, "L2 chain ID of the chain to upgrade")
+	deployedPath := flag.String("deployed", "", "path to a YAML file with the currently deployed contract versions")
+	format := flag.String("format", "plan", "output format, either plan or safe")
+	initPath := flag.String("init", "", "path to a JSON file with calldata to call on the upgraded proxies, by contract name")
+	flag.Parse()
+
+	if *deployedPath == "" {
+		return fmt.Errorf("missing -deployed flag")
+	}
+	deployed, err := readContractVersions(*deployedPath)
+	if err != nil {
+		return err
+	}
+	plan, err := superchain.NewUpgradePlan(*chainID, deployed)
+	if err != nil {
+		return err
+	}
+
+	var out any
+	switch *format {
+	case "plan":
+		out = plan
+	case "safe":
+		var initCalldata map[string]superchain.HexBytes
+		if *initPath != "" {
+			data, err := os.ReadFile(*initPath)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", *initPath, err)
+			}
+			if err := json.Unmarshal(data, &initCalldata); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", *initPath, err)
+			}
+		}
+		batch, err := superchain.NewSafeBatch(plan, initCalldata)
+		if err != nil {
+			return err
+		}
+		batch.CreatedAt = uint64(time.Now().UnixMilli())
+		out = batch
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func readContractVersions(path string) (superchain.ContractVersions, error) {
+	var versions superchain.ContractVersions
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return versions, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &versions); err != nil {
+		return versions, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	if err := versions.Check(); err != nil {
+		return versions, fmt.Errorf("invalid versions in %s: %w", path, err)
+	}
+	return versions, nil
+}
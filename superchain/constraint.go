@@ -0,0 +1,250 @@
+package superchain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Constraint is a semantic version constraint, following the npm conventions:
+//   - exact versions: "1.2.3", "v1.2.3" or "=1.2.3"
+//   - comparisons: ">1.2.3", ">=1.2.3", "<1.2.3", "<=1.2.3"
+//   - ranges of comparisons, which must all match: ">=1.5.0 <1.7.0"
+//   - wildcards: "*", "1.x", "1.2.x", or the partial versions "1" and "1.2"
+//   - caret ranges, allowing changes that do not modify the left-most non-zero part: "^1.2.3"
+//   - tilde ranges, allowing patch-level changes: "~1.2.3"
+//   - alternatives, of which one must match: "^1.0.0 || ^2.0.0"
+//
+// Versions with a pre-release tag only match a constraint if one of the comparisons
+// of the matching range has a pre-release tag on the same major, minor and patch version.
+type Constraint struct {
+	raw string
+	// sets of comparators. A version matches if all comparators of any of the sets match.
+	sets [][]comparator
+}
+
+type comparator struct {
+	op      string
+	version string // canonical semver, with "v" prefix
+}
+
+// ParseConstraint parses a semantic version constraint.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: s}
+	for _, alt := range strings.Split(s, "||") {
+		tokens := strings.Fields(alt)
+		if len(tokens) == 0 {
+			return Constraint{}, fmt.Errorf("empty constraint in %q", s)
+		}
+		var set []comparator
+		for i := 0; i < len(tokens); i++ {
+			tok := tokens[i]
+			// allow whitespace between the operator and version, e.g. ">= 1.2.3"
+			if isConstraintOperator(tok) {
+				if i+1 == len(tokens) {
+					return Constraint{}, fmt.Errorf("missing version after %q in %q", tok, s)
+				}
+				i++
+				tok += tokens[i]
+			}
+			comps, err := parseComparator(tok)
+			if err != nil {
+				return Constraint{}, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			set = append(set, comps...)
+		}
+		c.sets = append(c.sets, set)
+	}
+	return c, nil
+}
+
+// Matches returns whether the given version satisfies the constraint.
+// The version may be prefixed with a "v". Invalid versions never match.
+func (c Constraint) Matches(version string) bool {
+	version = canonicalizeSemver(version)
+	if !semver.IsValid(version) {
+		return false
+	}
+	for _, set := range c.sets {
+		if matchesAll(set, version) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+func matchesAll(set []comparator, version string) bool {
+	for _, comp := range set {
+		if !comp.matches(version) {
+			return false
+		}
+	}
+	if semver.Prerelease(version) == "" {
+		return true
+	}
+	// Pre-release versions only match if explicitly opted into for the same version triple.
+	triple := strings.TrimSuffix(version, semver.Prerelease(version))
+	for _, comp := range set {
+		if semver.Prerelease(comp.version) != "" && strings.TrimSuffix(comp.version, semver.Prerelease(comp.version)) == triple {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) matches(version string) bool {
+	res := semver.Compare(version, c.version)
+	switch c.op {
+	case "=":
+		return res == 0
+	case ">":
+		return res > 0
+	case ">=":
+		return res >= 0
+	case "<":
+		return res < 0
+	case "<=":
+		return res <= 0
+	default:
+		return false
+	}
+}
+
+func isConstraintOperator(s string) bool {
+	switch s {
+	case "=", ">", ">=", "<", "<=", "^", "~":
+		return true
+	default:
+		return false
+	}
+}
+
+// partialVersion is a version of which the minor and patch parts may be omitted or wildcards.
+type partialVersion struct {
+	parts [3]uint64
+	// n is the number of specified parts, the remaining parts are wildcards.
+	n          int
+	prerelease string
+}
+
+func (p partialVersion) String() string {
+	return fmt.Sprintf("v%d.%d.%d%s", p.parts[0], p.parts[1], p.parts[2], p.prerelease)
+}
+
+// bump returns the lowest version that is greater than all versions matching
+// the first n parts of the partial version.
+func (p partialVersion) bump(n int) string {
+	out := partialVersion{n: 3}
+	copy(out.parts[:n], p.parts[:n])
+	out.parts[n-1]++
+	return out.String()
+}
+
+func parsePartialVersion(s string) (partialVersion, error) {
+	var out partialVersion
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i] // build metadata is ignored
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		out.prerelease = s[i:]
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return out, fmt.Errorf("too many version parts in %q", s)
+	}
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			// all following parts must be wildcards too
+			for _, rest := range parts[i+1:] {
+				if rest != "x" && rest != "X" && rest != "*" {
+					return out, fmt.Errorf("unexpected version part %q after wildcard", rest)
+				}
+			}
+			break
+		}
+		v, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || (len(part) > 1 && part[0] == '0') {
+			return out, fmt.Errorf("invalid version part %q", part)
+		}
+		out.parts[i] = v
+		out.n++
+	}
+	if out.prerelease != "" {
+		if out.n != 3 {
+			return out, fmt.Errorf("pre-release tag requires a full version, got %q", s)
+		}
+		if !semver.IsValid(out.String()) {
+			return out, fmt.Errorf("invalid pre-release tag %q", out.prerelease)
+		}
+	}
+	return out, nil
+}
+
+// parseComparator parses a single comparator, desugaring wildcard, caret and tilde ranges
+// into basic comparisons.
+func parseComparator(s string) ([]comparator, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(s, prefix) {
+			op = prefix
+			break
+		}
+	}
+	v, err := parsePartialVersion(strings.TrimPrefix(s, op))
+	if err != nil {
+		return nil, err
+	}
+	if v.n == 0 {
+		switch op {
+		case "", "=", ">=", "<=", "^", "~":
+			return nil, nil // matches anything
+		default:
+			return nil, fmt.Errorf("%q matches nothing", s)
+		}
+	}
+	lower := comparator{">=", v.String()}
+	switch op {
+	case "", "=":
+		if v.n == 3 {
+			return []comparator{{"=", v.String()}}, nil
+		}
+		return []comparator{lower, {"<", v.bump(v.n)}}, nil
+	case ">":
+		if v.n == 3 {
+			return []comparator{{">", v.String()}}, nil
+		}
+		return []comparator{{">=", v.bump(v.n)}}, nil
+	case ">=":
+		return []comparator{lower}, nil
+	case "<":
+		return []comparator{{"<", v.String()}}, nil
+	case "<=":
+		if v.n == 3 {
+			return []comparator{{"<=", v.String()}}, nil
+		}
+		return []comparator{{"<", v.bump(v.n)}}, nil
+	case "~":
+		if v.n == 1 {
+			return []comparator{lower, {"<", v.bump(1)}}, nil
+		}
+		return []comparator{lower, {"<", v.bump(2)}}, nil
+	case "^":
+		switch {
+		case v.parts[0] > 0 || v.n == 1:
+			return []comparator{lower, {"<", v.bump(1)}}, nil
+		case v.parts[1] > 0 || v.n == 2:
+			return []comparator{lower, {"<", v.bump(2)}}, nil
+		default:
+			return []comparator{lower, {"<", v.bump(3)}}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
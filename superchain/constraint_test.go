@@ -0,0 +1,55 @@
+package superchain
+
+import "testing"
+
+func TestConstraint(t *testing.T) {
+	cases := []struct {
+		constraint string
+		matches    []string
+		excludes   []string
+	}{
+		{"1.2.3", []string{"1.2.3", "v1.2.3"}, []string{"1.2.4", "1.2.3-rc.1"}},
+		{"=v1.2.3", []string{"1.2.3"}, []string{"1.2.2"}},
+		{"*", []string{"0.0.1", "3.2.1"}, []string{"1.0.0-rc.1"}},
+		{"1.x", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{"1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^1.x", []string{"1.0.0", "1.5.0"}, []string{"2.0.0"}},
+		{"~1.5.0", []string{"1.5.0", "1.5.9"}, []string{"1.6.0", "1.4.9"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">=1.5.0 <1.7.0", []string{"1.5.0", "1.6.9"}, []string{"1.7.0", "1.4.0"}},
+		{">= 1.5.0 < 1.7.0", []string{"1.6.0"}, []string{"1.7.0"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"^1.0.0 || ^3.0.0", []string{"1.1.0", "3.1.0"}, []string{"2.0.0"}},
+		{">=1.7.0-beta.1", []string{"1.7.0-beta.2", "1.7.0", "1.8.0"}, []string{"1.8.0-beta.1", "1.7.0-alpha"}},
+	}
+	for _, test := range cases {
+		t.Run(test.constraint, func(t *testing.T) {
+			c, err := ParseConstraint(test.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range test.matches {
+				if !c.Matches(v) {
+					t.Errorf("expected %s to match", v)
+				}
+			}
+			for _, v := range test.excludes {
+				if c.Matches(v) {
+					t.Errorf("expected %s to not match", v)
+				}
+			}
+		})
+	}
+}
+
+func TestConstraintInvalid(t *testing.T) {
+	for _, s := range []string{"", "foo", "1.2.3.4", "^1.x.2", "1.2-rc.1", ">=", "01.2.3", "1.2.3 ||", "<*"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
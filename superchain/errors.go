@@ -0,0 +1,16 @@
+package superchain
+
+import "errors"
+
+// Sentinel errors of registry lookups and loaders.
+// Returned errors wrap these with context, match them with errors.Is.
+var (
+	// ErrUnknownChain is returned for chains that are not in the registry.
+	ErrUnknownChain = errors.New("unknown chain")
+	// ErrUnknownSuperchain is returned for superchain targets that are not in the registry.
+	ErrUnknownSuperchain = errors.New("unknown superchain target")
+	// ErrMissingBytecode is returned for code hashes without bytecode in extra/bytecodes.
+	ErrMissingBytecode = errors.New("missing bytecode")
+	// ErrCorruptData is returned for registry data that cannot be decoded, or does not match its hash.
+	ErrCorruptData = errors.New("corrupt registry data")
+)
@@ -0,0 +1,180 @@
+package superchain
+
+import (
+	"fmt"
+	"io/fs"
+	"math/big"
+)
+
+// emptyUncleHash is the hash of an empty list of uncles, keccak256(rlp([])).
+var emptyUncleHash = keccak256(rlpList())
+
+// StorageRoot computes the root of the storage trie of the account.
+func (a *GenesisAccount) StorageRoot() Hash {
+	trie := NewSecureTrie()
+	for k, v := range a.Storage {
+		if trimmed := trimLeadingZeros(v[:]); len(trimmed) > 0 {
+			trie.Update(k[:], rlpBytes(trimmed))
+		}
+	}
+	return trie.Hash()
+}
+
+// StateRoot computes the state root of the genesis block.
+// The code of every account is loaded with LoadContractBytecode, and checked against its code hash.
+// If the genesis only has a StateHash, instead of an Alloc, the StateHash is returned.
+func (g *Genesis) StateRoot() (Hash, error) {
+	return genesisStateRoot(globalFS, g)
+}
+
+// GenesisStateRoot is like Genesis.StateRoot, but loads the bytecode from the registry.
+func (r *Registry) GenesisStateRoot(g *Genesis) (Hash, error) {
+	return genesisStateRoot(r.fsys, g)
+}
+
+func genesisStateRoot(fsys fs.FS, g *Genesis) (Hash, error) {
+	if g.StateHash != nil {
+		if len(g.Alloc) > 0 {
+			return Hash{}, fmt.Errorf("genesis contains both an allocation of %d accounts and state hash %s", len(g.Alloc), *g.StateHash)
+		}
+		return *g.StateHash, nil
+	}
+	checked := map[Hash]bool{}
+	for addr, acc := range g.Alloc {
+		if acc.CodeHash == (Hash{}) || acc.CodeHash == emptyCodeHash || checked[acc.CodeHash] {
+			continue
+		}
+		code, err := loadContractBytecode(fsys, acc.CodeHash)
+		if err != nil {
+			return Hash{}, fmt.Errorf("failed to load code of account %s: %w", addr, err)
+		}
+		if h := keccak256(code); h != acc.CodeHash {
+			return Hash{}, fmt.Errorf("%w: code of account %s hashes to %s, expected %s", ErrCorruptData, addr, h, acc.CodeHash)
+		}
+		checked[acc.CodeHash] = true
+	}
+	return g.stateRoot(), nil
+}
+
+// stateRoot returns the StateHash if set, or computes the root of the state trie of the Alloc otherwise.
+// Unlike StateRoot, it does not check the code of the accounts.
+func (g *Genesis) stateRoot() Hash {
+	if g.StateHash != nil {
+		return *g.StateHash
+	}
+	trie := NewSecureTrie()
+	for addr, acc := range g.Alloc {
+		codeHash := acc.CodeHash
+		if codeHash == (Hash{}) {
+			codeHash = emptyCodeHash
+		}
+		storageRoot := acc.StorageRoot()
+		trie.Update(addr[:], rlpList(rlpUint(uint64(acc.Nonce)), rlpBig((*big.Int)(acc.Balance)), rlpBytes(storageRoot[:]), rlpBytes(codeHash[:])))
+	}
+	return trie.Hash()
+}
+
+// BlockHash computes the hash of the genesis block header.
+// The header includes the base fee if it is set, the withdrawals root
+// if Canyon (Shanghai) is active at the genesis timestamp, and the blob gas fields
+// and parent beacon block root if Ecotone (Cancun) is active at the genesis timestamp.
+// Chains may override the fork times of their superchain target,
+// see ChainBlockHash to use the fork schedule of the chain instead.
+func (g *Genesis) BlockHash(superchain *SuperchainConfig) Hash {
+	var hardforks HardforkTable
+	if superchain != nil {
+		hardforks = superchain.Hardforks
+	}
+	return g.blockHash(hardforks, g.stateRoot())
+}
+
+// ChainBlockHash is like BlockHash, but uses the effective fork schedule of the chain.
+func (g *Genesis) ChainBlockHash(chain *ChainConfig) Hash {
+	return g.blockHash(chain.Hardforks, g.stateRoot())
+}
+
+func (g *Genesis) blockHash(hardforks HardforkTable, root Hash) Hash {
+	return keccak256(rlpList(g.headerFields(hardforks, root)...))
+}
+
+// headerFields returns the RLP encoded fields of the genesis block header.
+func (g *Genesis) headerFields(hardforks HardforkTable, root Hash) [][]byte {
+	var nonce [8]byte
+	for i := 0; i < 8; i++ {
+		nonce[7-i] = byte(g.Nonce >> (8 * i))
+	}
+	var bloom [256]byte
+	fields := [][]byte{
+		rlpBytes(g.ParentHash[:]),
+		rlpBytes(emptyUncleHash[:]),
+		rlpBytes(g.Coinbase[:]),
+		rlpBytes(root[:]),
+		rlpBytes(emptyRootHash[:]), // transactions
+		rlpBytes(emptyRootHash[:]), // receipts
+		rlpBytes(bloom[:]),
+		rlpBig((*big.Int)(g.Difficulty)),
+		rlpUint(uint64(g.Number)),
+		rlpUint(uint64(g.GasLimit)),
+		rlpUint(uint64(g.GasUsed)),
+		rlpUint(uint64(g.Timestamp)),
+		rlpBytes(g.ExtraData),
+		rlpBytes(g.Mixhash[:]),
+		rlpBytes(nonce[:]),
+	}
+	if g.BaseFee == nil {
+		return fields
+	}
+	fields = append(fields, rlpBig((*big.Int)(g.BaseFee)))
+	if !hardforks.IsActive(Canyon, uint64(g.Timestamp)) {
+		return fields
+	}
+	fields = append(fields, rlpBytes(emptyRootHash[:])) // withdrawals
+	if !hardforks.IsActive(Ecotone, uint64(g.Timestamp)) {
+		return fields
+	}
+	var blobGasUsed, excessBlobGas uint64
+	if g.BlobGasUsed != nil {
+		blobGasUsed = uint64(*g.BlobGasUsed)
+	}
+	if g.ExcessBlobGas != nil {
+		excessBlobGas = uint64(*g.ExcessBlobGas)
+	}
+	var parentBeaconRoot Hash // the genesis block has no parent beacon block
+	return append(fields, rlpUint(blobGasUsed), rlpUint(excessBlobGas), rlpBytes(parentBeaconRoot[:]))
+}
+
+// VerifyGenesis computes the genesis block hash of the chain from its genesis definition,
+// and checks that it matches the L2 genesis hash of the chain config.
+func VerifyGenesis(chainID uint64) error {
+	return verifyGenesis(globalFS, OPChains, chainID)
+}
+
+// VerifyGenesis is like the package-level VerifyGenesis, but uses the chains of the registry.
+func (r *Registry) VerifyGenesis(chainID uint64) error {
+	return verifyGenesis(r.fsys, r.OPChains, chainID)
+}
+
+func verifyGenesis(fsys fs.FS, chains map[uint64]*ChainConfig, chainID uint64) error {
+	genesis, err := loadGenesis(fsys, chains, chainID)
+	if err != nil {
+		return err
+	}
+	chain := chains[chainID]
+	expected := chain.Genesis.L2.Hash
+	if uint64(genesis.Number) != chain.Genesis.L2.Number {
+		// The genesis definition of migrated chains is the legacy genesis, not the Bedrock transition block.
+		if chain.Genesis.LegacyHash == nil {
+			return fmt.Errorf("genesis number %d of chain %d does not match L2 genesis number %d",
+				genesis.Number, chainID, chain.Genesis.L2.Number)
+		}
+		expected = *chain.Genesis.LegacyHash
+	}
+	root, err := genesisStateRoot(fsys, genesis)
+	if err != nil {
+		return fmt.Errorf("failed to compute state root of chain %d: %w", chainID, err)
+	}
+	if got := genesis.blockHash(chain.Hardforks, root); got != expected {
+		return fmt.Errorf("computed genesis hash %s of chain %d does not match expected hash %s", got, chainID, expected)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package superchain
+
+import (
+	"fmt"
+	"io/fs"
+	"math/big"
+)
+
+// GethGenesis is a complete genesis definition in the genesis.json format of geth and op-geth,
+// with the bytecode of all accounts inlined, and the chain config included.
+type GethGenesis struct {
+	Config     *GethChainConfig               `json:"config"`
+	Nonce      HexUint64                      `json:"nonce"`
+	Timestamp  HexUint64                      `json:"timestamp"`
+	ExtraData  HexBytes                       `json:"extraData"`
+	GasLimit   HexUint64                      `json:"gasLimit"`
+	Difficulty *HexBig                        `json:"difficulty"`
+	Mixhash    Hash                           `json:"mixHash"`
+	Coinbase   Address                        `json:"coinbase"`
+	Alloc      map[Address]GethGenesisAccount `json:"alloc"`
+	Number     HexUint64                      `json:"number"`
+	GasUsed    HexUint64                      `json:"gasUsed"`
+	ParentHash Hash                           `json:"parentHash"`
+	BaseFee    *HexBig                        `json:"baseFeePerGas"`
+	// ExcessBlobGas and BlobGasUsed are only set if Ecotone (Cancun) is active at genesis.
+	ExcessBlobGas *HexUint64 `json:"excessBlobGas,omitempty"`
+	BlobGasUsed   *HexUint64 `json:"blobGasUsed,omitempty"`
+	// StateHash is only set for chains that were migrated to Bedrock,
+	// which have no genesis allocation, see Genesis.StateHash.
+	StateHash *Hash `json:"stateHash,omitempty"`
+}
+
+type GethGenesisAccount struct {
+	Code    HexBytes      `json:"code,omitempty"`
+	Storage map[Hash]Hash `json:"storage,omitempty"`
+	Balance *HexBig       `json:"balance"`
+	Nonce   HexUint64     `json:"nonce,omitempty"`
+}
+
+// GethChainConfig is the chain config of op-geth.
+// Unlike the genesis fields, the fork activations are plain JSON numbers, as op-geth expects.
+type GethChainConfig struct {
+	ChainID uint64 `json:"chainId"`
+
+	HomesteadBlock      *uint64 `json:"homesteadBlock,omitempty"`
+	EIP150Block         *uint64 `json:"eip150Block,omitempty"`
+	EIP155Block         *uint64 `json:"eip155Block,omitempty"`
+	EIP158Block         *uint64 `json:"eip158Block,omitempty"`
+	ByzantiumBlock      *uint64 `json:"byzantiumBlock,omitempty"`
+	ConstantinopleBlock *uint64 `json:"constantinopleBlock,omitempty"`
+	PetersburgBlock     *uint64 `json:"petersburgBlock,omitempty"`
+	IstanbulBlock       *uint64 `json:"istanbulBlock,omitempty"`
+	MuirGlacierBlock    *uint64 `json:"muirGlacierBlock,omitempty"`
+	BerlinBlock         *uint64 `json:"berlinBlock,omitempty"`
+	LondonBlock         *uint64 `json:"londonBlock,omitempty"`
+	ArrowGlacierBlock   *uint64 `json:"arrowGlacierBlock,omitempty"`
+	GrayGlacierBlock    *uint64 `json:"grayGlacierBlock,omitempty"`
+	MergeNetsplitBlock  *uint64 `json:"mergeNetsplitBlock,omitempty"`
+
+	ShanghaiTime *uint64 `json:"shanghaiTime,omitempty"`
+	CancunTime   *uint64 `json:"cancunTime,omitempty"`
+
+	BedrockBlock *uint64 `json:"bedrockBlock,omitempty"`
+	RegolithTime *uint64 `json:"regolithTime,omitempty"`
+	CanyonTime   *uint64 `json:"canyonTime,omitempty"`
+	DeltaTime    *uint64 `json:"deltaTime,omitempty"`
+	EcotoneTime  *uint64 `json:"ecotoneTime,omitempty"`
+	FjordTime    *uint64 `json:"fjordTime,omitempty"`
+
+	// TerminalTotalDifficulty is a big integer, like in geth, as the TTD of L1 chains exceeds 64 bits.
+	TerminalTotalDifficulty       *big.Int `json:"terminalTotalDifficulty"`
+	TerminalTotalDifficultyPassed bool     `json:"terminalTotalDifficultyPassed"`
+
+	Optimism *GethOptimismConfig `json:"optimism,omitempty"`
+}
+
+type GethOptimismConfig struct {
+	EIP1559Elasticity        uint64 `json:"eip1559Elasticity"`
+	EIP1559Denominator       uint64 `json:"eip1559Denominator"`
+	EIP1559DenominatorCanyon uint64 `json:"eip1559DenominatorCanyon"`
+}
+
+// The EIP-1559 parameters of OP Stack chains, unless the chain config sets different ones.
+const (
+	defaultEIP1559Elasticity        = 6
+	defaultEIP1559Denominator       = 50
+	defaultEIP1559DenominatorCanyon = 250
+)
+
+func u64ptr(v uint64) *uint64 {
+	return &v
+}
+
+// newGethChainConfig synthesizes the op-geth chain config of a chain from the registry data.
+func newGethChainConfig(chain *ChainConfig, genesis *Genesis) *GethChainConfig {
+	zero := u64ptr(0)
+	cfg := &GethChainConfig{
+		ChainID:             chain.ChainID,
+		HomesteadBlock:      zero,
+		EIP150Block:         zero,
+		EIP155Block:         zero,
+		EIP158Block:         zero,
+		ByzantiumBlock:      zero,
+		ConstantinopleBlock: zero,
+		PetersburgBlock:     zero,
+		IstanbulBlock:       zero,
+		MuirGlacierBlock:    zero,
+		BerlinBlock:         zero,
+		LondonBlock:         zero,
+		ArrowGlacierBlock:   zero,
+		GrayGlacierBlock:    zero,
+		MergeNetsplitBlock:  zero,
+		BedrockBlock:        zero,
+		RegolithTime:        chain.Hardforks.timePtr(Regolith),
+		CanyonTime:          chain.Hardforks.timePtr(Canyon),
+		// Canyon activates the Shanghai upgrade of L1.
+		ShanghaiTime: chain.Hardforks.timePtr(Canyon),
+		DeltaTime:    chain.Hardforks.timePtr(Delta),
+		EcotoneTime:  chain.Hardforks.timePtr(Ecotone),
+		// Ecotone activates the Cancun upgrade of L1.
+		CancunTime: chain.Hardforks.timePtr(Ecotone),
+		FjordTime:  chain.Hardforks.timePtr(Fjord),
+
+		TerminalTotalDifficulty:       new(big.Int),
+		TerminalTotalDifficultyPassed: true,
+
+		Optimism: &GethOptimismConfig{
+			EIP1559Elasticity:        defaultEIP1559Elasticity,
+			EIP1559Denominator:       defaultEIP1559Denominator,
+			EIP1559DenominatorCanyon: defaultEIP1559DenominatorCanyon,
+		},
+	}
+	if chain.BerlinBlock != nil {
+		cfg.BerlinBlock = u64ptr(*chain.BerlinBlock)
+	}
+	if chain.EIP1559Elasticity != nil {
+		cfg.Optimism.EIP1559Elasticity = *chain.EIP1559Elasticity
+	}
+	if chain.EIP1559Denominator != nil {
+		cfg.Optimism.EIP1559Denominator = *chain.EIP1559Denominator
+	}
+	if chain.EIP1559DenominatorCanyon != nil {
+		cfg.Optimism.EIP1559DenominatorCanyon = *chain.EIP1559DenominatorCanyon
+	}
+	if uint64(genesis.Number) != chain.Genesis.L2.Number {
+		// Chains migrated to Bedrock activate London and Bedrock at the Bedrock transition block.
+		bedrock := u64ptr(chain.Genesis.L2.Number)
+		cfg.LondonBlock = bedrock
+		cfg.ArrowGlacierBlock = bedrock
+		cfg.GrayGlacierBlock = bedrock
+		cfg.MergeNetsplitBlock = bedrock
+		cfg.BedrockBlock = bedrock
+	}
+	return cfg
+}
+
+// ExportGenesis returns the complete genesis definition of the chain, to initialize op-geth with.
+func ExportGenesis(chainID uint64) (*GethGenesis, error) {
+	return exportGenesis(globalFS, OPChains, chainID)
+}
+
+// ExportGenesis is like the package-level ExportGenesis, but uses the chains of the registry.
+func (r *Registry) ExportGenesis(chainID uint64) (*GethGenesis, error) {
+	return exportGenesis(r.fsys, r.OPChains, chainID)
+}
+
+func exportGenesis(fsys fs.FS, chains map[uint64]*ChainConfig, chainID uint64) (*GethGenesis, error) {
+	genesis, err := loadGenesis(fsys, chains, chainID)
+	if err != nil {
+		return nil, err
+	}
+	chain := chains[chainID]
+
+	out := &GethGenesis{
+		Config:     newGethChainConfig(chain, genesis),
+		Nonce:      genesis.Nonce,
+		Timestamp:  genesis.Timestamp,
+		ExtraData:  genesis.ExtraData,
+		GasLimit:   genesis.GasLimit,
+		Difficulty: genesis.Difficulty,
+		Mixhash:    genesis.Mixhash,
+		Coinbase:   genesis.Coinbase,
+		Alloc:      make(map[Address]GethGenesisAccount, len(genesis.Alloc)),
+		Number:     genesis.Number,
+		GasUsed:    genesis.GasUsed,
+		ParentHash: genesis.ParentHash,
+		BaseFee:    genesis.BaseFee,
+		StateHash:  genesis.StateHash,
+
+		ExcessBlobGas: genesis.ExcessBlobGas,
+		BlobGasUsed:   genesis.BlobGasUsed,
+	}
+	if out.Difficulty == nil {
+		out.Difficulty = (*HexBig)(new(big.Int))
+	}
+	codes := map[Hash][]byte{}
+	for addr, acc := range genesis.Alloc {
+		account := GethGenesisAccount{
+			Storage: acc.Storage,
+			Balance: acc.Balance,
+			Nonce:   acc.Nonce,
+		}
+		if account.Balance == nil {
+			account.Balance = (*HexBig)(new(big.Int))
+		}
+		if acc.CodeHash != (Hash{}) && acc.CodeHash != emptyCodeHash {
+			code, ok := codes[acc.CodeHash]
+			if !ok {
+				code, err = loadContractBytecode(fsys, acc.CodeHash)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load code of account %s: %w", addr, err)
+				}
+				codes[acc.CodeHash] = code
+			}
+			account.Code = code
+		}
+		out.Alloc[addr] = account
+	}
+	return out, nil
+}
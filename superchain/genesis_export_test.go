@@ -0,0 +1,150 @@
+package superchain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestExportGenesis checks that the exported genesis of every chain encodes to JSON and back,
+// and still hashes to the genesis block hash of the registry.
+func TestExportGenesis(t *testing.T) {
+	for id, chain := range OPChains {
+		exported, err := ExportGenesis(id)
+		if err != nil {
+			t.Fatalf("chain %d: %v", id, err)
+		}
+		data, err := json.Marshal(exported)
+		if err != nil {
+			t.Fatalf("chain %d: %v", id, err)
+		}
+		var decoded GethGenesis
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("chain %d: %v", id, err)
+		}
+		if decoded.Config.ChainID != id {
+			t.Errorf("chain %d: wrong config chain ID %d", id, decoded.Config.ChainID)
+		}
+
+		original, err := LoadGenesis(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, expected := fromGethGenesis(&decoded).ChainBlockHash(chain), original.ChainBlockHash(chain); got != expected {
+			t.Errorf("chain %d: exported genesis hashes to %s, expected %s", id, got, expected)
+		}
+	}
+}
+
+func fromGethGenesis(g *GethGenesis) *Genesis {
+	out := &Genesis{
+		Nonce:      g.Nonce,
+		Timestamp:  g.Timestamp,
+		ExtraData:  g.ExtraData,
+		GasLimit:   g.GasLimit,
+		Difficulty: g.Difficulty,
+		Mixhash:    g.Mixhash,
+		Coinbase:   g.Coinbase,
+		Number:     g.Number,
+		GasUsed:    g.GasUsed,
+		ParentHash: g.ParentHash,
+		BaseFee:    g.BaseFee,
+		Alloc:      map[Address]GenesisAccount{},
+		StateHash:  g.StateHash,
+
+		ExcessBlobGas: g.ExcessBlobGas,
+		BlobGasUsed:   g.BlobGasUsed,
+	}
+	for addr, acc := range g.Alloc {
+		account := GenesisAccount{Storage: acc.Storage, Balance: acc.Balance, Nonce: acc.Nonce}
+		if len(acc.Code) > 0 {
+			account.CodeHash = keccak256(acc.Code)
+		}
+		out.Alloc[addr] = account
+	}
+	return out
+}
+
+// TestGethChainConfigTTD checks that a geth chain config with the terminal total difficulty of an L1 chain decodes.
+func TestGethChainConfigTTD(t *testing.T) {
+	var cfg GethChainConfig
+	if err := json.Unmarshal([]byte(`{"chainId":1,"terminalTotalDifficulty":58750000000000000000000}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if ttd := cfg.TerminalTotalDifficulty.String(); ttd != "58750000000000000000000" {
+		t.Fatalf("wrong terminal total difficulty %s", ttd)
+	}
+}
+
+func TestExportGenesisConfig(t *testing.T) {
+	base, err := ExportGenesis(8453)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := base.Config
+	if cfg.BedrockBlock == nil || *cfg.BedrockBlock != 0 || cfg.RegolithTime == nil || *cfg.RegolithTime != 0 {
+		t.Errorf("Base Mainnet should activate Bedrock and Regolith at genesis")
+	}
+	canyon := Superchains["mainnet"].Config.CanyonTime
+	if cfg.CanyonTime == nil || *cfg.CanyonTime != *canyon || cfg.ShanghaiTime == nil || *cfg.ShanghaiTime != *canyon {
+		t.Errorf("Shanghai and Canyon should activate at the Canyon time of the superchain")
+	}
+	if cfg.BerlinBlock == nil || *cfg.BerlinBlock != 0 || cfg.Optimism.EIP1559Elasticity != defaultEIP1559Elasticity {
+		t.Errorf("Base Mainnet should have the default Berlin block and EIP-1559 elasticity")
+	}
+	if len(base.Alloc[HexToAddress("0x4200000000000000000000000000000000000016")].Code) == 0 {
+		t.Errorf("code of L2ToL1MessagePasser predeploy is not inlined")
+	}
+
+	op, err := ExportGenesis(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bedrock := OPChains[10].Genesis.L2.Number
+	if op.Config.BedrockBlock == nil || *op.Config.BedrockBlock != bedrock || *op.Config.LondonBlock != bedrock {
+		t.Errorf("OP Mainnet should activate Bedrock and London at block %d", bedrock)
+	}
+	if op.Config.BerlinBlock == nil || *op.Config.BerlinBlock != 3950000 {
+		t.Errorf("OP Mainnet should activate Berlin at the block of its chain config")
+	}
+
+	baseGoerli, err := ExportGenesis(84531)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opt := baseGoerli.Config.Optimism; opt.EIP1559Elasticity != 10 || opt.EIP1559Denominator != defaultEIP1559Denominator {
+		t.Errorf("Base Goerli should have the EIP-1559 elasticity of its chain config, got %+v", opt)
+	}
+	data, err := json.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"bedrockBlock":105235063`) || !strings.Contains(string(data), `"number":"0x0"`) {
+		t.Errorf("unexpected encoding of OP Mainnet genesis")
+	}
+}
+
+func TestExportGenesisEcotone(t *testing.T) {
+	overlay := privateDevnet(t, "4242")
+	overlay["configs/sepolia/private-devnet.yaml"].Data = []byte("name: Private Devnet\nchain_id: 4242\necotone_time: 1708534800\n")
+	reg, err := LoadOverlay(embeddedFS, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := reg.ExportGenesis(4242)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := exported.Config
+	if cfg.EcotoneTime == nil || *cfg.EcotoneTime != 1708534800 || cfg.CancunTime == nil || *cfg.CancunTime != 1708534800 {
+		t.Errorf("Cancun and Ecotone should activate at the Ecotone time of the chain")
+	}
+
+	base, err := ExportGenesis(8453)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.Config.CancunTime != nil {
+		t.Errorf("Cancun should not be scheduled without Ecotone")
+	}
+}
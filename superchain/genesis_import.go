@@ -0,0 +1,208 @@
+package superchain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// CompactGenesis converts a full genesis definition into the compact registry format,
+// where the code of every account is replaced by its code hash.
+// It returns the compact genesis, and the deduplicated bytecode by code hash.
+// The chain config of the full genesis is dropped, it is derived from the chain and superchain definition instead.
+func CompactGenesis(full *GethGenesis) (*Genesis, map[Hash][]byte) {
+	out := &Genesis{
+		Nonce:      full.Nonce,
+		Timestamp:  full.Timestamp,
+		ExtraData:  full.ExtraData,
+		GasLimit:   full.GasLimit,
+		Difficulty: full.Difficulty,
+		Mixhash:    full.Mixhash,
+		Coinbase:   full.Coinbase,
+		Number:     full.Number,
+		GasUsed:    full.GasUsed,
+		ParentHash: full.ParentHash,
+		BaseFee:    full.BaseFee,
+		StateHash:  full.StateHash,
+
+		ExcessBlobGas: full.ExcessBlobGas,
+		BlobGasUsed:   full.BlobGasUsed,
+	}
+	codes := map[Hash][]byte{}
+	if len(full.Alloc) > 0 {
+		out.Alloc = make(map[Address]GenesisAccount, len(full.Alloc))
+	}
+	for addr, acc := range full.Alloc {
+		account := GenesisAccount{
+			Storage: acc.Storage,
+			Nonce:   acc.Nonce,
+		}
+		if acc.Balance != nil && (*big.Int)(acc.Balance).Sign() != 0 {
+			account.Balance = acc.Balance
+		}
+		if len(acc.Code) > 0 {
+			account.CodeHash = keccak256(acc.Code)
+			codes[account.CodeHash] = acc.Code
+		}
+		out.Alloc[addr] = account
+	}
+	return out, codes
+}
+
+// EncodeGenesis encodes the genesis as gzipped JSON, as stored in extra/genesis.
+// The encoding is deterministic: the same genesis always encodes to the same bytes.
+func EncodeGenesis(g *Genesis) ([]byte, error) {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	return gzipBytes(data)
+}
+
+// EncodeContractBytecode encodes the bytecode as gzipped data, as stored in extra/bytecodes.
+// The encoding is deterministic: the same bytecode always encodes to the same bytes.
+func EncodeContractBytecode(code []byte) ([]byte, error) {
+	return gzipBytes(code)
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	// The zero gzip header has no name and no modification time, to keep the output deterministic.
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportGenesis compacts the full genesis definition of a chain, and writes it into the
+// registry directory dir: the genesis to extra/genesis/<superchainTarget>/<chain>.json.gz,
+// and all bytecode that is not yet registered to extra/bytecodes/<hash>.bin.gz.
+// The files are first written to a staging directory next to dir, and loaded back, together with the registry,
+// to verify that they match the full genesis. Only then are they moved into the registry,
+// so a failed import does not leave partial data in the registry.
+func ImportGenesis(dir, superchainTarget, chain string, full *GethGenesis) (*Genesis, error) {
+	genesis, codes := CompactGenesis(full)
+	registry := os.DirFS(dir)
+
+	// The staging directory is next to the registry directory, not inside it, so an interrupted import
+	// leaves no stray files in the registry. Being on the same filesystem, the staged files can be renamed into place.
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(absDir), ".import-genesis-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	codeHashes := make([]Hash, 0, len(codes))
+	for codeHash := range codes {
+		codeHashes = append(codeHashes, codeHash)
+	}
+	sort.Slice(codeHashes, func(i, j int) bool { return bytes.Compare(codeHashes[i][:], codeHashes[j][:]) < 0 })
+
+	var staged []string
+	for _, codeHash := range codeHashes {
+		code := codes[codeHash]
+		name := path.Join("extra", "bytecodes", codeHash.String()+".bin.gz")
+		if existing, err := loadContractBytecode(registry, codeHash); err == nil {
+			if !bytes.Equal(existing, code) {
+				return nil, fmt.Errorf("existing bytecode %s does not match the imported code", name)
+			}
+			continue
+		} else if !errors.Is(err, ErrMissingBytecode) {
+			return nil, err
+		}
+		data, err := EncodeContractBytecode(code)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeRegistryFile(staging, name, data); err != nil {
+			return nil, err
+		}
+		staged = append(staged, name)
+	}
+	data, err := EncodeGenesis(genesis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode genesis: %w", err)
+	}
+	genesisName := path.Join("extra", "genesis", superchainTarget, chain+".json.gz")
+	if err := writeRegistryFile(staging, genesisName, data); err != nil {
+		return nil, err
+	}
+	staged = append(staged, genesisName)
+
+	// The staged files replace those of the registry, e.g. when a genesis is imported again.
+	combined := &overlayFS{layers: []fs.FS{registry, os.DirFS(staging)}}
+	if err := verifyImport(combined, superchainTarget, chain, genesis, codes); err != nil {
+		return nil, fmt.Errorf("imported genesis does not round-trip: %w", err)
+	}
+	for _, name := range staged {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(filepath.Join(staging, filepath.FromSlash(name)), target); err != nil {
+			return nil, err
+		}
+	}
+	return genesis, nil
+}
+
+func writeRegistryFile(dir, name string, data []byte) error {
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o644)
+}
+
+// verifyImport loads the genesis and bytecode back from the registry filesystem,
+// and checks that they match the imported data.
+func verifyImport(fsys fs.FS, superchainTarget, chain string, genesis *Genesis, codes map[Hash][]byte) error {
+	// The chain may not be registered yet, so the genesis is loaded by its location only.
+	loaded, err := loadGenesis(fsys, map[uint64]*ChainConfig{0: {Superchain: superchainTarget, Chain: chain}}, 0)
+	if err != nil {
+		return err
+	}
+	expected, err := json.Marshal(genesis)
+	if err != nil {
+		return err
+	}
+	got, err := json.Marshal(loaded)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(got, expected) {
+		return errors.New("loaded genesis differs from the imported genesis")
+	}
+	for addr, acc := range loaded.Alloc {
+		if acc.CodeHash == (Hash{}) {
+			continue
+		}
+		code, err := loadContractBytecode(fsys, acc.CodeHash)
+		if err != nil {
+			return fmt.Errorf("failed to load code of account %s: %w", addr, err)
+		}
+		if !bytes.Equal(code, codes[acc.CodeHash]) {
+			return fmt.Errorf("loaded code of account %s differs from the imported code", addr)
+		}
+	}
+	return nil
+}
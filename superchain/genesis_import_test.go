@@ -0,0 +1,133 @@
+package superchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestImportGenesis checks that importing the exported genesis of a chain
+// reproduces the genesis and bytecode of the registry, deterministically.
+func TestImportGenesis(t *testing.T) {
+	full, err := ExportGenesis(8453)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	imported, err := ImportGenesis(dir, "mainnet", "base", full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := LoadGenesis(8453)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(imported)
+	expected, _ := json.Marshal(original)
+	if !bytes.Equal(got, expected) {
+		t.Fatal("imported genesis differs from the registry genesis")
+	}
+
+	genesisFile := filepath.Join(dir, "extra", "genesis", "mainnet", "base.json.gz")
+	first, err := os.ReadFile(genesisFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, err := os.ReadDir(filepath.Join(dir, "extra", "bytecodes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range codes {
+		var h Hash
+		if err := h.UnmarshalText([]byte(entry.Name()[:66])); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadContractBytecode(h); err != nil {
+			t.Errorf("imported bytecode %s is not in the registry: %v", h, err)
+		}
+	}
+
+	if _, err := ImportGenesis(dir, "mainnet", "base", full); err != nil {
+		t.Fatal(err)
+	}
+	second, err := os.ReadFile(genesisFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("genesis encoding is not deterministic")
+	}
+}
+
+func TestImportGenesisBytecodeMismatch(t *testing.T) {
+	full := &GethGenesis{Alloc: map[Address]GethGenesisAccount{
+		HexToAddress("0x4200000000000000000000000000000000000000"): {Code: HexBytes{0x60, 0x00}},
+	}}
+	dir := t.TempDir()
+	if _, err := ImportGenesis(dir, "devnet", "test", full); err != nil {
+		t.Fatal(err)
+	}
+	// Corrupt the registered bytecode, so it no longer matches its code hash.
+	name := filepath.Join(dir, "extra", "bytecodes", keccak256([]byte{0x60, 0x00}).String()+".bin.gz")
+	data, err := EncodeContractBytecode([]byte{0x60, 0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportGenesis(dir, "devnet", "test", full); err == nil {
+		t.Fatal("expected error for mismatching existing bytecode")
+	}
+}
+
+// TestImportGenesisFailure checks that a failed import leaves no files in the registry.
+func TestImportGenesisFailure(t *testing.T) {
+	codeA, codeB := []byte{0x60, 0x00}, []byte{0x60, 0x01}
+	if hashA, hashB := keccak256(codeA), keccak256(codeB); bytes.Compare(hashA[:], hashB[:]) > 0 {
+		codeA, codeB = codeB, codeA
+	}
+	full := &GethGenesis{Alloc: map[Address]GethGenesisAccount{
+		HexToAddress("0x4200000000000000000000000000000000000000"): {Code: codeA},
+		HexToAddress("0x4200000000000000000000000000000000000001"): {Code: codeB},
+	}}
+	dir := t.TempDir()
+	// The bytecode that is checked last is corrupt, after the first bytecode is already staged.
+	corrupt := filepath.Join("extra", "bytecodes", keccak256(codeB).String()+".bin.gz")
+	if err := writeRegistryFile(dir, filepath.ToSlash(corrupt), []byte("not gzip")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportGenesis(dir, "devnet", "test", full); !errors.Is(err, ErrCorruptData) {
+		t.Fatalf("expected ErrCorruptData, got %v", err)
+	}
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != dir {
+			rel, _ := filepath.Rel(dir, p)
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"extra", filepath.Join("extra", "bytecodes"), corrupt}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("failed import left files %v, expected only %v", files, expected)
+	}
+	siblings, err := os.ReadDir(filepath.Dir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(siblings) != 1 {
+		t.Errorf("failed import left a staging directory next to the registry: %v", siblings)
+	}
+}
@@ -0,0 +1,274 @@
+package superchain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fork is the name of a network upgrade of the OP Stack, as used in the "<fork>_time" config keys.
+type Fork string
+
+const (
+	Regolith Fork = "regolith"
+	Canyon   Fork = "canyon"
+	Delta    Fork = "delta"
+	Ecotone  Fork = "ecotone"
+	Fjord    Fork = "fjord"
+)
+
+// Forks are all known forks, in activation order.
+// A fork can only activate together with, or after, the forks before it.
+var Forks = []Fork{Regolith, Canyon, Delta, Ecotone, Fjord}
+
+// legacyForkNames maps former fork names, that may still be used in configs, to the current name.
+var legacyForkNames = map[Fork]Fork{
+	"eclipse": Ecotone,
+}
+
+// nonForkTimeKeys are config keys that end in "_time", but are not fork activation times.
+var nonForkTimeKeys = map[string]bool{
+	"block_time": true,
+	"l2_time":    true,
+}
+
+// index returns the position of the fork in Forks, or -1 if the fork is unknown.
+func (f Fork) index() int {
+	for i, fork := range Forks {
+		if fork == f {
+			return i
+		}
+	}
+	return -1
+}
+
+// displayName returns the capitalized fork name, e.g. Canyon.
+func (f Fork) displayName() string {
+	if f == "" {
+		return ""
+	}
+	return strings.ToUpper(string(f[:1])) + string(f[1:])
+}
+
+// HardforkTable holds the activation times of the scheduled forks.
+// Forks that are not in the table are not scheduled.
+type HardforkTable map[Fork]uint64
+
+// Time returns the activation time of the fork, if it is scheduled.
+func (h HardforkTable) Time(fork Fork) (uint64, bool) {
+	t, ok := h[fork]
+	return t, ok
+}
+
+// timePtr returns the activation time of the fork, or nil if it is not scheduled.
+func (h HardforkTable) timePtr(fork Fork) *uint64 {
+	t, ok := h[fork]
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+// IsActive returns whether the fork is active at the given timestamp.
+func (h HardforkTable) IsActive(fork Fork, timestamp uint64) bool {
+	t, ok := h[fork]
+	return ok && t <= timestamp
+}
+
+// ActiveForks returns the forks that are active at the given timestamp, in activation order.
+func (h HardforkTable) ActiveForks(timestamp uint64) []Fork {
+	var out []Fork
+	for _, fork := range Forks {
+		if h.IsActive(fork, timestamp) {
+			out = append(out, fork)
+		}
+	}
+	return out
+}
+
+// NextFork returns the first fork that activates after the given timestamp, and its activation time.
+func (h HardforkTable) NextFork(timestamp uint64) (fork Fork, activation uint64, ok bool) {
+	for _, f := range Forks {
+		if t, scheduled := h[f]; scheduled && t > timestamp {
+			return f, t, true
+		}
+	}
+	return "", 0, false
+}
+
+// Validate checks that all forks are known, and that the forks are scheduled in order:
+// every fork before a scheduled fork must be scheduled too, at the same time or earlier.
+func (h HardforkTable) Validate() error {
+	var unknown []string
+	for fork := range h {
+		if fork.index() < 0 {
+			unknown = append(unknown, string(fork))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown forks: %s", strings.Join(unknown, ", "))
+	}
+	if errs := h.checkOrder("", 0); len(errs) > 0 {
+		return fmt.Errorf("fork %s at %d %s", errs[0].Fork, errs[0].Time, errs[0].Reason)
+	}
+	return nil
+}
+
+// decodeHardforks collects the "<fork>_time" keys of a YAML mapping into a HardforkTable.
+func decodeHardforks(value *yaml.Node) (HardforkTable, error) {
+	var fields map[string]yaml.Node
+	if err := value.Decode(&fields); err != nil {
+		return nil, err
+	}
+	out := HardforkTable{}
+	for key, node := range fields {
+		name, ok := strings.CutSuffix(key, "_time")
+		if !ok || nonForkTimeKeys[key] {
+			continue
+		}
+		fork := Fork(name)
+		if current, ok := legacyForkNames[fork]; ok {
+			fork = current
+		}
+		if fork.index() < 0 {
+			return nil, fmt.Errorf("unknown fork %q in %s", name, key)
+		}
+		if _, ok := out[fork]; ok {
+			return nil, fmt.Errorf("fork %s is configured more than once", fork)
+		}
+		var t uint64
+		if err := node.Decode(&t); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		out[fork] = t
+	}
+	return out, nil
+}
+
+// encodeHardforks encodes the struct value as a YAML mapping, with a "<fork>_time" key per fork of the table appended,
+// the inverse of decodeHardforks. Forks at their default time, as given by skip, are left out.
+func encodeHardforks(v any, hardforks HardforkTable, skip func(Fork, uint64) bool) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping, got YAML node kind %d", node.Kind)
+	}
+	for _, fork := range Forks {
+		t, ok := hardforks[fork]
+		if !ok || (skip != nil && skip(fork, t)) {
+			continue
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(fork) + "_time"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatUint(t, 10)},
+		)
+	}
+	return &node, nil
+}
+
+// ForkScheduleError is an invalid fork activation in the schedule of a superchain target or chain.
+type ForkScheduleError struct {
+	Superchain string
+	// ChainID is the chain of the invalid activation, or 0 for the hardforks of the superchain target.
+	ChainID uint64
+	Fork    Fork
+	Time    uint64
+	Reason  string
+}
+
+func (e *ForkScheduleError) Error() string {
+	if e.ChainID == 0 {
+		return fmt.Sprintf("superchain %s: fork %s at %d %s", e.Superchain, e.Fork, e.Time, e.Reason)
+	}
+	return fmt.Sprintf("chain %d (%s): fork %s at %d %s", e.ChainID, e.Superchain, e.Fork, e.Time, e.Reason)
+}
+
+// checkOrder returns the fork activations of the table that are not ordered after their predecessor.
+func (h HardforkTable) checkOrder(superchain string, chainID uint64) []*ForkScheduleError {
+	var errs []*ForkScheduleError
+	for i, fork := range Forks {
+		t, ok := h[fork]
+		if !ok || i == 0 {
+			continue
+		}
+		prev, prevOK := h[Forks[i-1]]
+		if !prevOK {
+			errs = append(errs, &ForkScheduleError{superchain, chainID, fork, t,
+				fmt.Sprintf("is scheduled, but the preceding fork %s is not", Forks[i-1])})
+		} else if prev > t {
+			errs = append(errs, &ForkScheduleError{superchain, chainID, fork, t,
+				fmt.Sprintf("activates before the preceding fork %s at %d", Forks[i-1], prev)})
+		}
+	}
+	return errs
+}
+
+// CheckForkSchedule checks the effective fork schedule of the chain:
+// every fork must be ordered after its predecessor, and must activate on an L2 block timestamp,
+// not before the L2 genesis. Forks at time 0 activate at genesis, and are always valid.
+// All invalid activations are returned as joined *ForkScheduleError errors.
+func (c *ChainConfig) CheckForkSchedule() error {
+	var errs []error
+	for _, err := range c.Hardforks.checkOrder(c.Superchain, c.ChainID) {
+		errs = append(errs, err)
+	}
+	for _, fork := range Forks {
+		t, ok := c.Hardforks[fork]
+		if !ok || t == 0 {
+			continue
+		}
+		if t < c.Genesis.L2Time {
+			errs = append(errs, &ForkScheduleError{c.Superchain, c.ChainID, fork, t,
+				fmt.Sprintf("activates before the L2 genesis at %d", c.Genesis.L2Time)})
+		} else if c.BlockTime != nil && (t-c.Genesis.L2Time)%*c.BlockTime != 0 {
+			errs = append(errs, &ForkScheduleError{c.Superchain, c.ChainID, fork, t,
+				fmt.Sprintf("is not on an L2 block timestamp, with genesis at %d and block time %d", c.Genesis.L2Time, *c.BlockTime)})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckForkSchedules checks the hardforks of all superchain targets, and the fork schedule of all chains.
+// All invalid activations are returned as joined *ForkScheduleError errors, ordered by superchain target and chain ID.
+func CheckForkSchedules() error {
+	return checkForkSchedules(Superchains, OPChains)
+}
+
+// CheckForkSchedules is like the package-level CheckForkSchedules, but checks the chains of the registry.
+func (r *Registry) CheckForkSchedules() error {
+	return checkForkSchedules(r.Superchains, r.OPChains)
+}
+
+func checkForkSchedules(superchains map[string]*Superchain, chains map[uint64]*ChainConfig) error {
+	targets := make([]string, 0, len(superchains))
+	for name := range superchains {
+		targets = append(targets, name)
+	}
+	sort.Strings(targets)
+	var errs []error
+	for _, name := range targets {
+		sc := superchains[name]
+		for _, err := range sc.Config.Hardforks.checkOrder(name, 0) {
+			errs = append(errs, err)
+		}
+		chainIDs := append([]uint64{}, sc.ChainIDs...)
+		sort.Slice(chainIDs, func(i, j int) bool { return chainIDs[i] < chainIDs[j] })
+		for _, id := range chainIDs {
+			chain, ok := chains[id]
+			if !ok {
+				continue
+			}
+			if err := chain.CheckForkSchedule(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,162 @@
+package superchain
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestHardforkTable(t *testing.T) {
+	h := HardforkTable{Regolith: 0, Canyon: 100, Delta: 200, Ecotone: 200}
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if !h.IsActive(Regolith, 0) || h.IsActive(Canyon, 99) || !h.IsActive(Canyon, 100) || h.IsActive(Fjord, 1000) {
+		t.Error("unexpected IsActive result")
+	}
+	if got := h.ActiveForks(150); !reflect.DeepEqual(got, []Fork{Regolith, Canyon}) {
+		t.Errorf("unexpected active forks %v", got)
+	}
+	if got := h.ActiveForks(200); !reflect.DeepEqual(got, []Fork{Regolith, Canyon, Delta, Ecotone}) {
+		t.Errorf("unexpected active forks %v", got)
+	}
+	if fork, at, ok := h.NextFork(100); !ok || fork != Delta || at != 200 {
+		t.Errorf("unexpected next fork %s at %d", fork, at)
+	}
+	if _, _, ok := h.NextFork(200); ok {
+		t.Error("expected no next fork")
+	}
+
+	for name, invalid := range map[string]HardforkTable{
+		"gap":       {Regolith: 0, Canyon: 100, Ecotone: 200},
+		"unordered": {Regolith: 0, Canyon: 200, Delta: 100},
+		"unknown":   {Regolith: 0, "granite": 100},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestSuperchainConfigHardforks(t *testing.T) {
+	var cfg SuperchainConfig
+	data := "name: Test\nl1:\n  chain_id: 1\ncanyon_time: 100\ndelta_time: 200\neclipse_time: 300\n"
+	if err := yaml.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	expected := HardforkTable{Regolith: 0, Canyon: 100, Delta: 200, Ecotone: 300}
+	if !reflect.DeepEqual(cfg.Hardforks, expected) {
+		t.Errorf("unexpected hardforks %v", cfg.Hardforks)
+	}
+	if cfg.Name != "Test" || *cfg.CanyonTime != 100 || *cfg.EclipseTime != 300 || cfg.FjordTime != nil {
+		t.Error("unexpected superchain config fields")
+	}
+
+	for _, invalid := range []string{
+		"name: Test\ngranite_time: 100\n",
+		"name: Test\necotone_time: 100\neclipse_time: 100\n",
+		"name: Test\ncanyon_time: soon\n",
+	} {
+		if err := yaml.Unmarshal([]byte(invalid), &cfg); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+
+	mainnet := Superchains["mainnet"].Config.Hardforks
+	if at, ok := mainnet.Time(Canyon); !ok || at != 1704992401 {
+		t.Errorf("unexpected mainnet Canyon time %d", at)
+	}
+}
+
+func TestCheckForkSchedule(t *testing.T) {
+	chain := &ChainConfig{
+		ChainID:          123,
+		Superchain:       "test",
+		Genesis:          ChainGenesis{L2Time: 1000},
+		RollupParameters: RollupParameters{BlockTime: u64ptr(2)},
+		Hardforks:        HardforkTable{Regolith: 0, Canyon: 1000, Delta: 1002, Ecotone: 1010},
+	}
+	if err := chain.CheckForkSchedule(); err != nil {
+		t.Fatal(err)
+	}
+
+	chain.Hardforks = HardforkTable{Regolith: 0, Canyon: 900, Delta: 1003, Ecotone: 1002}
+	err := chain.CheckForkSchedule()
+	if err == nil {
+		t.Fatal("expected invalid fork schedule")
+	}
+	invalid := map[Fork]bool{}
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var scheduleErr *ForkScheduleError
+		if !errors.As(err, &scheduleErr) {
+			t.Fatalf("unexpected error type %T", err)
+		}
+		if scheduleErr.ChainID != 123 || scheduleErr.Superchain != "test" {
+			t.Errorf("error does not name the chain: %v", scheduleErr)
+		}
+		invalid[scheduleErr.Fork] = true
+	}
+	// Canyon is before genesis, Delta is not on a block timestamp, and Ecotone is before Delta.
+	if !reflect.DeepEqual(invalid, map[Fork]bool{Canyon: true, Delta: true, Ecotone: true}) {
+		t.Errorf("unexpected invalid forks %v: %v", invalid, err)
+	}
+}
+
+// TestConfigYAMLRoundTrip checks that every superchain and chain config encodes its hardforks,
+// and decodes to the same config again.
+func TestConfigYAMLRoundTrip(t *testing.T) {
+	targets, err := fs.ReadDir(embeddedFS, "configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range targets {
+		if !target.IsDir() {
+			continue
+		}
+		files, err := fs.ReadDir(embeddedFS, path.Join("configs", target.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			name := path.Join("configs", target.Name(), file.Name())
+			data, err := fs.ReadFile(embeddedFS, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if file.Name() == "superchain.yaml" {
+				roundTripYAML[SuperchainConfig](t, name, data)
+			} else if strings.HasSuffix(file.Name(), ".yaml") {
+				roundTripYAML[ChainConfig](t, name, data)
+			}
+		}
+	}
+
+	sc := roundTripYAML[SuperchainConfig](t, "test", []byte("name: Test\ncanyon_time: 100\necotone_time: 200\n"))
+	if !reflect.DeepEqual(sc.Hardforks, HardforkTable{Regolith: 0, Canyon: 100, Ecotone: 200}) {
+		t.Errorf("unexpected hardforks after round trip: %v", sc.Hardforks)
+	}
+}
+
+func roundTripYAML[T any](t *testing.T, name string, data []byte) *T {
+	t.Helper()
+	var first, second T
+	if err := yaml.Unmarshal(data, &first); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	encoded, err := yaml.Marshal(first)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if err := yaml.Unmarshal(encoded, &second); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("%s does not round-trip:\n%s", name, encoded)
+	}
+	return &second
+}
@@ -0,0 +1,187 @@
+package superchain
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// AddressRef describes what a known L1 address is: a contract or role of a chain,
+// of a superchain target, or a contract implementation on an L1 chain.
+type AddressRef struct {
+	// L1ChainID is the L1 chain the address is on.
+	L1ChainID uint64
+	// Superchain is the superchain target of the address, unset for implementations,
+	// which are shared by all targets on the same L1 chain.
+	Superchain string
+	// ChainID is the OP chain of the address, zero for superchain and implementation addresses.
+	ChainID uint64
+	// Role is the name of the address in the registry, e.g. "OptimismPortalProxy", "Guardian",
+	// "BatchInbox" or "Batcher" for chains, "ProtocolVersions" or "SuperchainConfig" for superchain targets,
+	// and the contract name, e.g. "OptimismPortal", for implementations.
+	Role string
+	// Version is the semantic version of an implementation, with a "v" prefix like ContractImplementations.Lookup,
+	// unset otherwise.
+	Version string
+}
+
+// registryIndex holds the indices of a registry, built once when it is loaded.
+// All lookups, by ID, name, path, L1 chain and address, use the index, so they always agree with each other.
+// It is immutable: chains that are added to the maps of a registry, or to the package-level globals,
+// after loading are not indexed. The package-level index only reflects the embedded registry,
+// or the registry of ApplyOverlayDirs.
+type registryIndex struct {
+	// byID maps the chain ID to the chain, like OPChains at load time.
+	byID map[uint64]*ChainConfig
+	// byPath maps the "<superchain>/<chain>" path of a chain, the key of chainids.json, to the chain.
+	byPath map[string]*ChainConfig
+	// byName maps the lowercased display name to the chains with that name.
+	byName map[string][]*ChainConfig
+	// byL1 maps the L1 chain ID to the chains that settle on it, sorted by chain ID.
+	byL1 map[uint64][]*ChainConfig
+	// byAddress maps every known address to the references to it, sorted.
+	byAddress map[Address][]AddressRef
+}
+
+func newRegistryIndex(r *Registry) *registryIndex {
+	idx := &registryIndex{
+		byID:      map[uint64]*ChainConfig{},
+		byPath:    map[string]*ChainConfig{},
+		byName:    map[string][]*ChainConfig{},
+		byL1:      map[uint64][]*ChainConfig{},
+		byAddress: map[Address][]AddressRef{},
+	}
+	add := func(addr Address, ref AddressRef) {
+		if addr != (Address{}) {
+			idx.byAddress[addr] = append(idx.byAddress[addr], ref)
+		}
+	}
+	for _, sc := range r.Superchains {
+		l1ChainID := sc.Config.L1.ChainID
+		if addr := sc.Config.ProtocolVersionsAddr; addr != nil {
+			add(*addr, AddressRef{L1ChainID: l1ChainID, Superchain: sc.Superchain, Role: "ProtocolVersions"})
+		}
+		if addr := sc.Config.SuperchainConfigAddr; addr != nil {
+			add(*addr, AddressRef{L1ChainID: l1ChainID, Superchain: sc.Superchain, Role: "SuperchainConfig"})
+		}
+		for _, id := range sc.ChainIDs {
+			chain, ok := r.OPChains[id]
+			if !ok {
+				continue
+			}
+			idx.byID[id] = chain
+			idx.byPath[sc.Superchain+"/"+chain.Chain] = chain
+			name := strings.ToLower(chain.Name)
+			idx.byName[name] = append(idx.byName[name], chain)
+			idx.byL1[l1ChainID] = append(idx.byL1[l1ChainID], chain)
+
+			chainRef := func(role string) AddressRef {
+				return AddressRef{L1ChainID: l1ChainID, Superchain: sc.Superchain, ChainID: id, Role: role}
+			}
+			if addrs, ok := r.Addresses[id]; ok {
+				for _, named := range addrs.namedAddresses() {
+					add(named.addr, chainRef(named.role))
+				}
+			}
+			add(chain.BatchInboxAddr, chainRef("BatchInbox"))
+			if sysCfg, ok := r.GenesisSystemConfigs[id]; ok {
+				add(sysCfg.BatcherAddr, chainRef("Batcher"))
+			}
+		}
+	}
+	for l1ChainID, impls := range r.Implementations {
+		for _, named := range impls.namedSets() {
+			for version, addr := range named.set {
+				add(addr, AddressRef{L1ChainID: l1ChainID, Role: named.contract, Version: canonicalizeSemver(version)})
+			}
+		}
+	}
+
+	for _, chains := range idx.byName {
+		sortChains(chains)
+	}
+	for _, chains := range idx.byL1 {
+		sortChains(chains)
+	}
+	for _, refs := range idx.byAddress {
+		sort.Slice(refs, func(i, j int) bool { return refs[i].less(refs[j]) })
+	}
+	return idx
+}
+
+func sortChains(chains []*ChainConfig) {
+	sort.Slice(chains, func(i, j int) bool { return chains[i].ChainID < chains[j].ChainID })
+}
+
+func (a AddressRef) less(b AddressRef) bool {
+	if a.L1ChainID != b.L1ChainID {
+		return a.L1ChainID < b.L1ChainID
+	}
+	if a.Superchain != b.Superchain {
+		return a.Superchain < b.Superchain
+	}
+	if a.ChainID != b.ChainID {
+		return a.ChainID < b.ChainID
+	}
+	if a.Role != b.Role {
+		return a.Role < b.Role
+	}
+	return a.Version < b.Version
+}
+
+type namedAddress struct {
+	role string
+	addr Address
+}
+
+// namedAddresses returns all addresses of the list, named after their JSON key.
+// The addresses are found by reflection, so new fields of ProtocolContracts and PrivilegedRoles are included.
+func (a *AddressList) namedAddresses() []namedAddress {
+	val := reflect.ValueOf(a).Elem()
+	var out []namedAddress
+	for _, field := range reflect.VisibleFields(val.Type()) {
+		addr, ok := val.FieldByIndex(field.Index).Interface().(Address)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		out = append(out, namedAddress{name, addr})
+	}
+	return out
+}
+
+// ChainsByL1 returns the chains that settle on the given L1 chain, sorted by chain ID.
+// Like all lookups, it uses the index of the loaded registry.
+func ChainsByL1(l1ChainID uint64) []*ChainConfig {
+	return globalIndex.chainsByL1(l1ChainID)
+}
+
+// ChainsByL1 is like the package-level ChainsByL1, but returns the chains of the registry.
+func (r *Registry) ChainsByL1(l1ChainID uint64) []*ChainConfig {
+	return r.index.chainsByL1(l1ChainID)
+}
+
+func (idx *registryIndex) chainsByL1(l1ChainID uint64) []*ChainConfig {
+	return append([]*ChainConfig(nil), idx.byL1[l1ChainID]...)
+}
+
+// LookupAddress returns what the given L1 address is known as in the registry:
+// a proxy, privileged role, batch inbox or batcher of a chain, a contract of a superchain target,
+// or a contract implementation. It returns nil if the address is unknown.
+// An address may have multiple references, e.g. a multisig that holds several roles.
+// Like all lookups, it uses the index of the loaded registry.
+func LookupAddress(addr Address) []AddressRef {
+	return globalIndex.lookupAddress(addr)
+}
+
+// LookupAddress is like the package-level LookupAddress, but looks up the addresses of the registry.
+func (r *Registry) LookupAddress(addr Address) []AddressRef {
+	return r.index.lookupAddress(addr)
+}
+
+func (idx *registryIndex) lookupAddress(addr Address) []AddressRef {
+	return append([]AddressRef(nil), idx.byAddress[addr]...)
+}
@@ -0,0 +1,30 @@
+// Package abi holds the ABI encoding and hashing helpers that are shared by the superchain and verify packages,
+// without making them part of the public API of either package.
+package abi
+
+import (
+	"encoding/binary"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Keccak256 returns the legacy Keccak-256 hash of the value, as used by the EVM.
+func Keccak256(v []byte) [32]byte {
+	st := sha3.NewLegacyKeccak256()
+	st.Write(v)
+	return *(*[32]byte)(st.Sum(nil))
+}
+
+// Word left-pads the value to a 32 byte ABI word.
+func Word(v []byte) []byte {
+	out := make([]byte, 32)
+	copy(out[32-len(v):], v)
+	return out
+}
+
+// Uint encodes the value as a 32 byte ABI word.
+func Uint(v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return Word(b[:])
+}
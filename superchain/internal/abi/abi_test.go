@@ -0,0 +1,21 @@
+package abi
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestUint(t *testing.T) {
+	expected := "0000000000000000000000000000000000000000000000000000000000000120"
+	if got := hex.EncodeToString(Uint(0x120)); got != expected {
+		t.Fatalf("wrong ABI word: %s", got)
+	}
+}
+
+func TestKeccak256(t *testing.T) {
+	// The code hash of accounts without code.
+	expected := "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"
+	if got := Keccak256(nil); hex.EncodeToString(got[:]) != expected {
+		t.Fatalf("wrong hash: %x", got)
+	}
+}
@@ -0,0 +1,100 @@
+package superchain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChainByID returns the chain with the given chain ID.
+// Like all lookups, it uses the index of the loaded registry,
+// and does not find chains that were added to OPChains afterwards.
+func ChainByID(chainID uint64) (*ChainConfig, error) {
+	return globalIndex.chainByID(chainID)
+}
+
+// ChainByID is like the package-level ChainByID, but looks up the chains of the registry.
+func (r *Registry) ChainByID(chainID uint64) (*ChainConfig, error) {
+	return r.index.chainByID(chainID)
+}
+
+func (idx *registryIndex) chainByID(chainID uint64) (*ChainConfig, error) {
+	return chainByID(idx.byID, chainID)
+}
+
+func chainByID(chains map[uint64]*ChainConfig, chainID uint64) (*ChainConfig, error) {
+	chain, ok := chains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrUnknownChain, chainID)
+	}
+	return chain, nil
+}
+
+// ChainByName returns the chain with the given display name, e.g. "OP-Mainnet".
+// Names are matched case-insensitively.
+// Like all lookups, it uses the index of the loaded registry.
+func ChainByName(name string) (*ChainConfig, error) {
+	return globalIndex.chainByName(name)
+}
+
+// ChainByName is like the package-level ChainByName, but looks up the chains of the registry.
+func (r *Registry) ChainByName(name string) (*ChainConfig, error) {
+	return r.index.chainByName(name)
+}
+
+func (idx *registryIndex) chainByName(name string) (*ChainConfig, error) {
+	chains := idx.byName[strings.ToLower(name)]
+	switch len(chains) {
+	case 0:
+		return nil, fmt.Errorf("%w %q", ErrUnknownChain, name)
+	case 1:
+		return chains[0], nil
+	default:
+		return nil, fmt.Errorf("chain name %q is ambiguous: chains %d and %d", name, chains[0].ChainID, chains[1].ChainID)
+	}
+}
+
+// ChainByPath returns the chain at the given path "<superchain target>/<chain>",
+// the location of its config in the registry, e.g. "mainnet/op".
+// This is the same key that configs/chainids.json uses.
+// Like all lookups, it uses the index of the loaded registry.
+func ChainByPath(path string) (*ChainConfig, error) {
+	return globalIndex.chainByPath(Superchains, path)
+}
+
+// ChainByPath is like the package-level ChainByPath, but looks up the chains of the registry.
+func (r *Registry) ChainByPath(path string) (*ChainConfig, error) {
+	return r.index.chainByPath(r.Superchains, path)
+}
+
+func (idx *registryIndex) chainByPath(superchains map[string]*Superchain, path string) (*ChainConfig, error) {
+	target, name, ok := strings.Cut(path, "/")
+	if !ok || target == "" || name == "" {
+		return nil, fmt.Errorf("chain path %q is not of the form <superchain>/<chain>", path)
+	}
+	if _, err := superchainByName(superchains, target); err != nil {
+		return nil, err
+	}
+	chain, ok := idx.byPath[path]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownChain, path)
+	}
+	return chain, nil
+}
+
+// SuperchainByName returns the superchain target with the given identifier, e.g. "mainnet".
+func SuperchainByName(name string) (*Superchain, error) {
+	return superchainByName(Superchains, name)
+}
+
+// SuperchainByName is like the package-level SuperchainByName, but looks up the superchain targets of the registry.
+func (r *Registry) SuperchainByName(name string) (*Superchain, error) {
+	return superchainByName(r.Superchains, name)
+}
+
+func superchainByName(superchains map[string]*Superchain, name string) (*Superchain, error) {
+	sc, ok := superchains[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownSuperchain, name)
+	}
+	return sc, nil
+}
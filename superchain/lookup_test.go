@@ -0,0 +1,206 @@
+package superchain
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestChainLookups(t *testing.T) {
+	for _, lookup := range []func() (*ChainConfig, error){
+		func() (*ChainConfig, error) { return ChainByID(10) },
+		func() (*ChainConfig, error) { return ChainByName("OP-Mainnet") },
+		func() (*ChainConfig, error) { return ChainByName("op-mainnet") },
+		func() (*ChainConfig, error) { return ChainByPath("mainnet/op") },
+	} {
+		chain, err := lookup()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chain != OPChains[10] {
+			t.Fatalf("found chain %d, expected OP Mainnet", chain.ChainID)
+		}
+	}
+	if sc, err := SuperchainByName("mainnet"); err != nil || sc != Superchains["mainnet"] {
+		t.Fatalf("failed to look up mainnet superchain: %v", err)
+	}
+
+	for _, err := range []error{
+		func() error { _, err := ChainByID(4242); return err }(),
+		func() error { _, err := ChainByName("Unknown"); return err }(),
+		func() error { _, err := ChainByPath("mainnet/unknown"); return err }(),
+		func() error { _, err := ChainByPath("sepolia/pgn-mainnet"); return err }(),
+		func() error { _, err := LoadGenesis(4242); return err }(),
+		func() error { _, err := LoadRollupConfig(4242); return err }(),
+	} {
+		if !errors.Is(err, ErrUnknownChain) {
+			t.Errorf("expected ErrUnknownChain, got %v", err)
+		}
+	}
+	for _, err := range []error{
+		func() error { _, err := SuperchainByName("unknown"); return err }(),
+		func() error { _, err := ChainByPath("unknown/op"); return err }(),
+		func() error { _, err := ImplementationsFor("unknown"); return err }(),
+	} {
+		if !errors.Is(err, ErrUnknownSuperchain) {
+			t.Errorf("expected ErrUnknownSuperchain, got %v", err)
+		}
+	}
+	for _, path := range []string{"mainnet", "/op", "mainnet/"} {
+		if _, err := ChainByPath(path); err == nil {
+			t.Errorf("expected error for invalid path %q", path)
+		}
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	if _, err := LoadContractBytecode(Hash{1}); !errors.Is(err, ErrMissingBytecode) {
+		t.Errorf("expected ErrMissingBytecode, got %v", err)
+	}
+	code := []byte{0x60, 0x00}
+	codeHash := keccak256(code)
+	overlay := privateDevnet(t, "4242")
+	overlay["extra/bytecodes/"+codeHash.String()+".bin.gz"] = &fstest.MapFile{Data: code} // not gzipped
+	overlay["extra/genesis/sepolia/private-devnet.json.gz"] = &fstest.MapFile{Data: gzipData(t, []byte(`{"alloc": `))}
+	reg, err := LoadOverlay(embeddedFS, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.LoadContractBytecode(codeHash); !errors.Is(err, ErrCorruptData) {
+		t.Errorf("expected ErrCorruptData for bytecode, got %v", err)
+	}
+	if _, err := reg.LoadGenesis(4242); !errors.Is(err, ErrCorruptData) {
+		t.Errorf("expected ErrCorruptData for genesis, got %v", err)
+	}
+}
+
+func TestIndexLookups(t *testing.T) {
+	data, err := fs.ReadFile(embeddedFS, "configs/chainids.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chainIDs map[string]uint64
+	if err := json.Unmarshal(data, &chainIDs); err != nil {
+		t.Fatal(err)
+	}
+	if len(chainIDs) != len(OPChains) {
+		t.Fatalf("chainids.json has %d chains, registry has %d", len(chainIDs), len(OPChains))
+	}
+	for path, id := range chainIDs {
+		chain, err := ChainByPath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chain.ChainID != id {
+			t.Errorf("chain at path %s has chain ID %d, expected %d", path, chain.ChainID, id)
+		}
+	}
+
+	sepolia := ChainsByL1(11155111)
+	if len(sepolia) == 0 {
+		t.Fatal("no chains on Sepolia")
+	}
+	for i, chain := range sepolia {
+		if l1 := Superchains[chain.Superchain].Config.L1.ChainID; l1 != 11155111 {
+			t.Errorf("chain %d settles on L1 %d, not Sepolia", chain.ChainID, l1)
+		}
+		if i > 0 && sepolia[i-1].ChainID >= chain.ChainID {
+			t.Errorf("chains on Sepolia are not sorted by chain ID")
+		}
+	}
+	if chains := ChainsByL1(4242); len(chains) != 0 {
+		t.Errorf("expected no chains on unknown L1, got %d", len(chains))
+	}
+
+	hasRef := func(addr Address, expected AddressRef) {
+		t.Helper()
+		for _, ref := range LookupAddress(addr) {
+			if ref == expected {
+				return
+			}
+		}
+		t.Errorf("address %s has references %+v, missing %+v", addr, LookupAddress(addr), expected)
+	}
+	opRef := func(role string) AddressRef {
+		return AddressRef{L1ChainID: 1, Superchain: "mainnet", ChainID: 10, Role: role}
+	}
+	hasRef(Addresses[10].OptimismPortalProxy, opRef("OptimismPortalProxy"))
+	hasRef(Addresses[10].Guardian, opRef("Guardian"))
+	hasRef(OPChains[10].BatchInboxAddr, opRef("BatchInbox"))
+	hasRef(GenesisSystemConfigs[10].BatcherAddr, opRef("Batcher"))
+	hasRef(*Superchains["mainnet"].Config.ProtocolVersionsAddr,
+		AddressRef{L1ChainID: 1, Superchain: "mainnet", Role: "ProtocolVersions"})
+	for version, addr := range Implementations[1].OptimismPortal {
+		hasRef(addr, AddressRef{L1ChainID: 1, Role: "OptimismPortal", Version: canonicalizeSemver(version)})
+	}
+	for _, addrs := range Implementations {
+		for _, named := range addrs.namedSets() {
+			for _, addr := range named.set {
+				contract, version, _ := addrs.Lookup(addr)
+				found := false
+				for _, ref := range LookupAddress(addr) {
+					found = found || (ref.Role == contract && ref.Version == version)
+				}
+				if !found {
+					t.Errorf("implementation %s is %s %s, but indexed as %+v", addr, contract, version, LookupAddress(addr))
+				}
+			}
+		}
+	}
+	if refs := LookupAddress(Address{0x42}); refs != nil {
+		t.Errorf("expected no references for unknown address, got %+v", refs)
+	}
+}
+
+// TestAddressListNames checks that every JSON key of AddressList is a role in the address index.
+func TestAddressListNames(t *testing.T) {
+	var list AddressList
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys map[string]any
+	if err := json.Unmarshal(data, &keys); err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, named := range list.namedAddresses() {
+		names[named.role] = true
+	}
+	if len(names) != len(keys) {
+		t.Errorf("address list has %d JSON keys, but %d named addresses", len(keys), len(names))
+	}
+	for key := range keys {
+		if !names[key] {
+			t.Errorf("address list key %s is not a named address", key)
+		}
+	}
+}
+
+// TestLookupsAgree checks that all lookups use the loaded registry, also after its maps are modified.
+func TestLookupsAgree(t *testing.T) {
+	reg, err := LoadOverlay(embeddedFS, privateDevnet(t, "4242"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := &ChainConfig{Name: "Added", ChainID: 4343, Superchain: "sepolia", Chain: "added"}
+	reg.OPChains[added.ChainID] = added
+	if _, err := reg.ChainByID(4343); !errors.Is(err, ErrUnknownChain) {
+		t.Errorf("chain added after loading should not be found by ID: %v", err)
+	}
+	if _, err := reg.ChainByName("Added"); !errors.Is(err, ErrUnknownChain) {
+		t.Errorf("chain added after loading should not be found by name: %v", err)
+	}
+	delete(reg.OPChains, 4242)
+	for _, lookup := range []func() (*ChainConfig, error){
+		func() (*ChainConfig, error) { return reg.ChainByID(4242) },
+		func() (*ChainConfig, error) { return reg.ChainByName("Private Devnet") },
+		func() (*ChainConfig, error) { return reg.ChainByPath("sepolia/private-devnet") },
+	} {
+		if chain, err := lookup(); err != nil || chain.ChainID != 4242 {
+			t.Errorf("loaded chain should still be found: %v", err)
+		}
+	}
+}
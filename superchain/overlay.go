@@ -0,0 +1,128 @@
+package superchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// overlayFS layers filesystems on top of a base filesystem.
+//
+// The precedence rules are:
+//   - Directory listings are merged across all layers.
+//   - A file that exists in multiple overlays is read from the last overlay that has it.
+//   - Overlays may not replace files of the base layer, unless the contents are identical.
+//     This check is performed by newOverlayFS.
+//   - Chain IDs must be unique across all layers. This is checked when the registry is loaded.
+type overlayFS struct {
+	// layers are ordered from lowest to highest precedence, the base is the first layer.
+	layers []fs.FS
+}
+
+var _ fs.ReadDirFS = (*overlayFS)(nil)
+
+// newOverlayFS creates an overlayFS, and checks that none of the overlays modify files of the base.
+func newOverlayFS(base fs.FS, overlays ...fs.FS) (*overlayFS, error) {
+	for i, overlay := range overlays {
+		err := fs.WalkDir(overlay, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			baseData, err := fs.ReadFile(base, name)
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to read base file %s: %w", name, err)
+			}
+			data, err := fs.ReadFile(overlay, name)
+			if err != nil {
+				return fmt.Errorf("failed to read overlay file %s: %w", name, err)
+			}
+			if !bytes.Equal(baseData, data) {
+				return fmt.Errorf("overlay file %s conflicts with base file", name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid overlay %d: %w", i, err)
+		}
+	}
+	return &overlayFS{layers: append([]fs.FS{base}, overlays...)}, nil
+}
+
+func (o *overlayFS) Open(name string) (fs.File, error) {
+	for i := len(o.layers) - 1; i >= 0; i-- {
+		f, err := o.layers[i].Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o *overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries := map[string]fs.DirEntry{}
+	found := false
+	for _, layer := range o.layers {
+		layerEntries, err := fs.ReadDir(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		found = true
+		for _, e := range layerEntries {
+			entries[e.Name()] = e // higher layers replace lower layers
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	out := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
+
+// LoadOverlay loads a Registry from the base filesystem, with the given overlays layered on top.
+// Overlays follow the same layout as the base, e.g. configs/<superchain>/<chain>.yaml
+// and extra/addresses/<superchain>/<chain>.json.
+// Chains may be added to existing superchain targets, or to new superchain targets,
+// which then also require an implementations/networks/<superchain>.yaml file.
+//
+// Later overlays take precedence over earlier overlays. Overlays may not modify files of the base,
+// and chains of all layers must have unique chain IDs.
+func LoadOverlay(base fs.FS, overlays ...fs.FS) (*Registry, error) {
+	fsys, err := newOverlayFS(base, overlays...)
+	if err != nil {
+		return nil, err
+	}
+	return Load(fsys)
+}
+
+// ApplyOverlayDirs layers the given directories over the embedded registry data,
+// and replaces the package-level globals with the combined registry.
+// This should be called once, before the globals are used, as it is not safe for concurrent use.
+func ApplyOverlayDirs(dirs ...string) error {
+	overlays := make([]fs.FS, len(dirs))
+	for i, dir := range dirs {
+		overlays[i] = os.DirFS(dir)
+	}
+	reg, err := LoadOverlay(embeddedFS, overlays...)
+	if err != nil {
+		return fmt.Errorf("failed to load overlays: %w", err)
+	}
+	setGlobals(reg)
+	return nil
+}
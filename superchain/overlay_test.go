@@ -0,0 +1,166 @@
+package superchain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func gzipData(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// privateDevnet returns an overlay that adds a chain to the sepolia superchain target.
+func privateDevnet(t *testing.T, chainID string) fstest.MapFS {
+	code := []byte{0x60, 0x00}
+	return fstest.MapFS{
+		"configs/sepolia/private-devnet.yaml":                      {Data: []byte("name: Private Devnet\nchain_id: " + chainID + "\n")},
+		"extra/addresses/sepolia/private-devnet.json":              {Data: []byte(`{"ProxyAdmin": "0x0000000000000000000000000000000000000042"}`)},
+		"extra/genesis-system-configs/sepolia/private-devnet.json": {Data: []byte(`{"gasLimit": 30000000}`)},
+		"extra/genesis/sepolia/private-devnet.json.gz":             {Data: gzipData(t, []byte(`{"alloc": {}}`))},
+		"extra/bytecodes/" + keccak256(code).String() + ".bin.gz":  {Data: gzipData(t, code)},
+	}
+}
+
+func TestLoadOverlay(t *testing.T) {
+	reg, err := LoadOverlay(embeddedFS, privateDevnet(t, "4242"))
+	if err != nil {
+		t.Fatalf("failed to load overlay: %v", err)
+	}
+	if len(reg.OPChains) != len(OPChains)+1 {
+		t.Fatalf("expected one more chain than embedded, got %d", len(reg.OPChains))
+	}
+	ch, ok := reg.OPChains[4242]
+	if !ok || ch.Superchain != "sepolia" || ch.Chain != "private-devnet" {
+		t.Fatalf("overlay chain was not loaded: %v", ch)
+	}
+	if reg.Addresses[4242].ProxyAdmin != HexToAddress("0x0000000000000000000000000000000000000042") {
+		t.Fatal("wrong overlay addresses")
+	}
+	found := false
+	for _, id := range reg.Superchains["sepolia"].ChainIDs {
+		found = found || id == 4242
+	}
+	if !found {
+		t.Fatal("overlay chain is not part of its superchain")
+	}
+	if _, err := reg.LoadGenesis(4242); err != nil {
+		t.Fatalf("failed to load overlay genesis: %v", err)
+	}
+	if _, err := reg.LoadGenesis(10); err != nil {
+		t.Fatalf("failed to load embedded genesis: %v", err)
+	}
+	if _, err := reg.LoadContractBytecode(keccak256([]byte{0x60, 0x00})); err != nil {
+		t.Fatalf("failed to load overlay bytecode: %v", err)
+	}
+	if err := reg.VerifyGenesis(10); err != nil {
+		t.Fatalf("failed to verify embedded genesis: %v", err)
+	}
+	if err := reg.VerifyGenesis(4242); err == nil {
+		t.Fatal("expected genesis hash mismatch of overlay chain without L2 genesis hash")
+	}
+}
+
+func TestLoadOverlayConflicts(t *testing.T) {
+	t.Run("embedded-chain-id", func(t *testing.T) {
+		if _, err := LoadOverlay(embeddedFS, privateDevnet(t, "10")); err == nil {
+			t.Fatal("expected chain ID conflict with embedded chain")
+		}
+	})
+	t.Run("overlay-chain-id", func(t *testing.T) {
+		other := fstest.MapFS{}
+		for name, f := range privateDevnet(t, "4242") {
+			other[strings.ReplaceAll(name, "private-devnet", "other-devnet")] = f
+		}
+		if _, err := LoadOverlay(embeddedFS, privateDevnet(t, "4242"), other); err == nil {
+			t.Fatal("expected chain ID conflict between overlays")
+		}
+	})
+	t.Run("modified-base-file", func(t *testing.T) {
+		overlay := fstest.MapFS{
+			"configs/mainnet/op.yaml": {Data: []byte("name: Fake\nchain_id: 10\n")},
+		}
+		if _, err := LoadOverlay(embeddedFS, overlay); err == nil {
+			t.Fatal("expected error when replacing a base file")
+		}
+	})
+	t.Run("identical-base-file", func(t *testing.T) {
+		data, err := fs.ReadFile(embeddedFS, "configs/mainnet/op.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		overlay := fstest.MapFS{"configs/mainnet/op.yaml": {Data: data}}
+		if _, err := LoadOverlay(embeddedFS, overlay); err != nil {
+			t.Fatalf("identical base file should be accepted: %v", err)
+		}
+	})
+}
+
+// TestOverlayPrecedence asserts that later overlays take precedence over earlier overlays.
+func TestOverlayPrecedence(t *testing.T) {
+	first := privateDevnet(t, "4242")
+	second := fstest.MapFS{
+		"extra/addresses/sepolia/private-devnet.json": {Data: []byte(`{"ProxyAdmin": "0x0000000000000000000000000000000000000043"}`)},
+	}
+	reg, err := LoadOverlay(embeddedFS, first, second)
+	if err != nil {
+		t.Fatalf("failed to load overlays: %v", err)
+	}
+	if reg.Addresses[4242].ProxyAdmin != HexToAddress("0x0000000000000000000000000000000000000043") {
+		t.Fatal("expected addresses of the last overlay")
+	}
+}
+
+func TestApplyOverlayDirs(t *testing.T) {
+	t.Cleanup(func() {
+		reg, err := Load(embeddedFS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		setGlobals(reg)
+	})
+
+	dir := t.TempDir()
+	for name, f := range privateDevnet(t, "4242") {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, f.Data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ApplyOverlayDirs(dir); err != nil {
+		t.Fatalf("failed to apply overlay: %v", err)
+	}
+	if _, ok := OPChains[4242]; !ok {
+		t.Fatal("overlay chain is not available in OPChains")
+	}
+	if _, ok := Addresses[4242]; !ok {
+		t.Fatal("overlay chain is not available in Addresses")
+	}
+	if _, err := LoadGenesis(4242); err != nil {
+		t.Fatalf("failed to load overlay genesis: %v", err)
+	}
+	if _, err := LoadContractBytecode(keccak256([]byte{0x60, 0x00})); err != nil {
+		t.Fatalf("failed to load overlay bytecode: %v", err)
+	}
+
+	if err := ApplyOverlayDirs(filepath.Join(dir, "missing")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not-exist error for missing overlay dir, got %v", err)
+	}
+}
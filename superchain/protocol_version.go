@@ -0,0 +1,264 @@
+package superchain
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProtocolVersion is the 32-byte protocol version, as stored by the ProtocolVersions contract.
+// The first byte is the version type, which determines the encoding of the remaining 31 bytes.
+// See the superchain-upgrades specification of the OP Stack.
+type ProtocolVersion [32]byte
+
+// ProtocolVersionType is the first byte of a ProtocolVersion.
+type ProtocolVersionType uint8
+
+// ProtocolVersionTypeV0 is the semantic version type:
+// 7 reserved zero bytes, an 8-byte build identifier, and the big-endian uint32
+// major, minor, patch and pre-release numbers.
+const ProtocolVersionTypeV0 ProtocolVersionType = 0
+
+// ProtocolVersionV0 is the decoded form of a ProtocolVersion of type 0.
+type ProtocolVersionV0 struct {
+	// Build identifies a non-standard build, it is zero for releases of the reference implementation.
+	Build [8]byte
+	Major uint32
+	Minor uint32
+	Patch uint32
+	// PreRelease is the pre-release number, or 0 if the version is not a pre-release.
+	PreRelease uint32
+}
+
+// Encode returns the ProtocolVersion of the semantic version.
+func (v ProtocolVersionV0) Encode() ProtocolVersion {
+	var out ProtocolVersion
+	out[0] = byte(ProtocolVersionTypeV0)
+	copy(out[8:16], v.Build[:])
+	binary.BigEndian.PutUint32(out[16:20], v.Major)
+	binary.BigEndian.PutUint32(out[20:24], v.Minor)
+	binary.BigEndian.PutUint32(out[24:28], v.Patch)
+	binary.BigEndian.PutUint32(out[28:32], v.PreRelease)
+	return out
+}
+
+func (v ProtocolVersionV0) String() string {
+	out := fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != 0 {
+		out += fmt.Sprintf("-%d", v.PreRelease)
+	}
+	if v.Build != ([8]byte{}) {
+		out += "+" + encodeHex(v.Build[:])
+	}
+	return out
+}
+
+// Type returns the version type of the protocol version.
+func (p ProtocolVersion) Type() ProtocolVersionType {
+	return ProtocolVersionType(p[0])
+}
+
+// Parse decodes a protocol version of type 0.
+func (p ProtocolVersion) Parse() (ProtocolVersionV0, error) {
+	if p.Type() != ProtocolVersionTypeV0 {
+		return ProtocolVersionV0{}, fmt.Errorf("unsupported protocol version type %d", p.Type())
+	}
+	if [7]byte(p[1:8]) != ([7]byte{}) {
+		return ProtocolVersionV0{}, errors.New("reserved bytes of protocol version are not zero")
+	}
+	var v ProtocolVersionV0
+	copy(v.Build[:], p[8:16])
+	v.Major = binary.BigEndian.Uint32(p[16:20])
+	v.Minor = binary.BigEndian.Uint32(p[20:24])
+	v.Patch = binary.BigEndian.Uint32(p[24:28])
+	v.PreRelease = binary.BigEndian.Uint32(p[28:32])
+	return v, nil
+}
+
+// String returns the semantic version, like v1.2.3-4+0x0102030405060708,
+// or the hex encoding if the protocol version is not of type 0.
+func (p ProtocolVersion) String() string {
+	if v, err := p.Parse(); err == nil {
+		return v.String()
+	}
+	return encodeHex(p[:])
+}
+
+func (p ProtocolVersion) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *ProtocolVersion) UnmarshalText(text []byte) error {
+	v, err := ParseProtocolVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// ParseProtocolVersion parses a semantic version, as formatted by ProtocolVersion.String,
+// or a 0x-prefixed hex encoding of the 32 bytes.
+func ParseProtocolVersion(s string) (ProtocolVersion, error) {
+	if has0xPrefix([]byte(s)) {
+		var p ProtocolVersion
+		err := decodeHex(p[:], []byte(s))
+		return p, err
+	}
+	rest, ok := strings.CutPrefix(s, "v")
+	if !ok {
+		return ProtocolVersion{}, fmt.Errorf("protocol version %q does not start with v or 0x", s)
+	}
+	var v ProtocolVersionV0
+	if core, build, ok := strings.Cut(rest, "+"); ok {
+		if err := decodeHex(v.Build[:], []byte(build)); err != nil {
+			return ProtocolVersion{}, fmt.Errorf("invalid build of protocol version %q: %w", s, err)
+		}
+		rest = core
+	}
+	if core, pre, ok := strings.Cut(rest, "-"); ok {
+		n, err := strconv.ParseUint(pre, 10, 32)
+		if err != nil || n == 0 {
+			return ProtocolVersion{}, fmt.Errorf("invalid pre-release of protocol version %q", s)
+		}
+		v.PreRelease = uint32(n)
+		rest = core
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return ProtocolVersion{}, fmt.Errorf("protocol version %q is not of the form vMAJOR.MINOR.PATCH", s)
+	}
+	var nums [3]uint32
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return ProtocolVersion{}, fmt.Errorf("invalid number %q in protocol version %q", part, s)
+		}
+		nums[i] = uint32(n)
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v.Encode(), nil
+}
+
+// ProtocolVersionComparison is the result of comparing two protocol versions:
+// positive if a version is ahead of the other, negative if it is outdated,
+// with a larger magnitude for a more significant difference.
+// Versions of different types or builds are not comparable.
+type ProtocolVersionComparison int
+
+const (
+	AheadMajor         ProtocolVersionComparison = 4
+	OutdatedMajor      ProtocolVersionComparison = -4
+	AheadMinor         ProtocolVersionComparison = 3
+	OutdatedMinor      ProtocolVersionComparison = -3
+	AheadPatch         ProtocolVersionComparison = 2
+	OutdatedPatch      ProtocolVersionComparison = -2
+	AheadPrerelease    ProtocolVersionComparison = 1
+	OutdatedPrerelease ProtocolVersionComparison = -1
+	Matching           ProtocolVersionComparison = 0
+	DiffVersionType    ProtocolVersionComparison = 100
+	DiffBuild          ProtocolVersionComparison = 101
+	EmptyVersion       ProtocolVersionComparison = 102
+)
+
+// comparable returns whether the compared versions were comparable.
+func (c ProtocolVersionComparison) comparable() bool {
+	return c >= OutdatedMajor && c <= AheadMajor
+}
+
+// Compare compares the protocol version p with other.
+// The zero protocol version, as returned by a ProtocolVersions contract without a version, is not comparable.
+func (p ProtocolVersion) Compare(other ProtocolVersion) ProtocolVersionComparison {
+	if p == (ProtocolVersion{}) || other == (ProtocolVersion{}) {
+		return EmptyVersion
+	}
+	if p.Type() != other.Type() {
+		return DiffVersionType
+	}
+	a, err := p.Parse()
+	if err != nil {
+		return DiffVersionType
+	}
+	b, err := other.Parse()
+	if err != nil {
+		return DiffVersionType
+	}
+	if a.Build != b.Build {
+		return DiffBuild
+	}
+	cmp := func(x, y uint32, ahead ProtocolVersionComparison) ProtocolVersionComparison {
+		if x > y {
+			return ahead
+		}
+		return -ahead
+	}
+	switch {
+	case a.Major != b.Major:
+		return cmp(a.Major, b.Major, AheadMajor)
+	case a.Minor != b.Minor:
+		return cmp(a.Minor, b.Minor, AheadMinor)
+	case a.Patch != b.Patch:
+		return cmp(a.Patch, b.Patch, AheadPatch)
+	case a.PreRelease != b.PreRelease:
+		// A release is ahead of all its pre-releases.
+		if a.PreRelease == 0 {
+			return AheadPrerelease
+		}
+		if b.PreRelease == 0 {
+			return OutdatedPrerelease
+		}
+		return cmp(a.PreRelease, b.PreRelease, AheadPrerelease)
+	}
+	return Matching
+}
+
+// ProtocolVersionSupport is the result of checking a node's protocol version
+// against the recommended and required protocol versions of a superchain target.
+type ProtocolVersionSupport int
+
+const (
+	// Supported means the node is at or ahead of the recommended version.
+	Supported ProtocolVersionSupport = iota
+	// UpgradeRecommended means the node is behind the recommended, but not behind the required version.
+	UpgradeRecommended
+	// UpgradeRequired means the node is behind the required version.
+	UpgradeRequired
+)
+
+func (s ProtocolVersionSupport) String() string {
+	switch s {
+	case Supported:
+		return "supported"
+	case UpgradeRecommended:
+		return "upgrade recommended"
+	case UpgradeRequired:
+		return "upgrade required"
+	default:
+		return fmt.Sprintf("ProtocolVersionSupport(%d)", int(s))
+	}
+}
+
+// CheckSupport checks the node protocol version p against the recommended and required versions.
+// Unset (zero) recommended or required versions are ignored.
+// It returns an error if a version is not comparable to the node version, e.g. because of a different build.
+func (p ProtocolVersion) CheckSupport(recommended, required ProtocolVersion) (ProtocolVersionSupport, error) {
+	if required != (ProtocolVersion{}) {
+		switch c := p.Compare(required); {
+		case !c.comparable():
+			return 0, fmt.Errorf("node version %s is not comparable to required version %s", p, required)
+		case c < 0:
+			return UpgradeRequired, nil
+		}
+	}
+	if recommended != (ProtocolVersion{}) {
+		switch c := p.Compare(recommended); {
+		case !c.comparable():
+			return 0, fmt.Errorf("node version %s is not comparable to recommended version %s", p, recommended)
+		case c < 0:
+			return UpgradeRecommended, nil
+		}
+	}
+	return Supported, nil
+}
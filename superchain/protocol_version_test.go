@@ -0,0 +1,93 @@
+package superchain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProtocolVersionEncoding(t *testing.T) {
+	v := ProtocolVersionV0{Build: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, Major: 1, Minor: 2, Patch: 3, PreRelease: 4}
+	encoded := v.Encode()
+	if expected := HexToHash("0x0000000000000000010203040506070800000001000000020000000300000004"); Hash(encoded) != expected {
+		t.Fatalf("unexpected encoding %x", encoded)
+	}
+	decoded, err := encoded.Parse()
+	if err != nil || decoded != v {
+		t.Fatalf("unexpected decoding %+v: %v", decoded, err)
+	}
+	if s := encoded.String(); s != "v1.2.3-4+0x0102030405060708" {
+		t.Fatalf("unexpected string %s", s)
+	}
+
+	for _, s := range []string{"v1.2.3-4+0x0102030405060708", "v0.0.0", "v3.1.0", "v6.0.0-1", "0x0100000000000000000000000000000000000000000000000000000000000001"} {
+		p, err := ParseProtocolVersion(s)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", s, err)
+		}
+		if p.String() != s {
+			t.Errorf("%s formats as %s", s, p)
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var other ProtocolVersion
+		if err := json.Unmarshal(data, &other); err != nil || other != p {
+			t.Errorf("%s does not round-trip through JSON: %v", s, err)
+		}
+	}
+	for _, s := range []string{"1.2.3", "v1.2", "v1.2.3.4", "v1.2.x", "v1.2.3-0", "v1.2.3+0x01", "v4294967296.0.0", "0x01"} {
+		if _, err := ParseProtocolVersion(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestProtocolVersionCompare(t *testing.T) {
+	mustParse := func(s string) ProtocolVersion {
+		p, err := ParseProtocolVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	for _, test := range []struct {
+		a, b     string
+		expected ProtocolVersionComparison
+	}{
+		{"v1.2.3", "v1.2.3", Matching},
+		{"v2.0.0", "v1.9.9", AheadMajor},
+		{"v1.1.0", "v1.2.0", OutdatedMinor},
+		{"v1.2.4", "v1.2.3", AheadPatch},
+		{"v1.2.3", "v1.2.3-1", AheadPrerelease},
+		{"v1.2.3-1", "v1.2.3", OutdatedPrerelease},
+		{"v1.2.3-2", "v1.2.3-1", AheadPrerelease},
+		{"v1.2.3+0x0000000000000001", "v1.2.3", DiffBuild},
+		{"0x0100000000000000000000000000000000000000000000000000000000000001", "v1.2.3", DiffVersionType},
+		{"v0.0.0", "v1.0.0", EmptyVersion},
+	} {
+		if got := mustParse(test.a).Compare(mustParse(test.b)); got != test.expected {
+			t.Errorf("%s compared to %s: got %d, expected %d", test.a, test.b, got, test.expected)
+		}
+	}
+
+	recommended, required := mustParse("v3.1.0"), mustParse("v3.0.0")
+	for node, expected := range map[string]ProtocolVersionSupport{
+		"v3.1.0":   Supported,
+		"v4.0.0":   Supported,
+		"v3.1.0-1": UpgradeRecommended,
+		"v3.0.5":   UpgradeRecommended,
+		"v2.9.0":   UpgradeRequired,
+	} {
+		got, err := mustParse(node).CheckSupport(recommended, required)
+		if err != nil || got != expected {
+			t.Errorf("node %s: got %s, expected %s: %v", node, got, expected, err)
+		}
+	}
+	if got, err := mustParse("v1.0.0").CheckSupport(ProtocolVersion{}, ProtocolVersion{}); err != nil || got != Supported {
+		t.Errorf("unset versions should be ignored: %s, %v", got, err)
+	}
+	if _, err := mustParse("v3.1.0+0x0000000000000001").CheckSupport(recommended, required); err == nil {
+		t.Error("expected error for a node of a different build")
+	}
+}
@@ -0,0 +1,275 @@
+package superchain
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Registry is a loaded set of superchain-targets, their chains, and all extra data
+// that is associated with them. Unlike the package-level globals, a Registry is
+// constructed without panicking, so candidate registry data can be loaded and validated
+// before it is used.
+//
+// The lookups by ID, name, path, L1 chain and address use an index that Load builds from the loaded data.
+// The index is not updated if the maps of the registry are modified afterwards.
+type Registry struct {
+	Superchains map[string]*Superchain
+
+	OPChains map[uint64]*ChainConfig
+
+	Addresses map[uint64]*AddressList
+
+	GenesisSystemConfigs map[uint64]*GenesisSystemConfig
+
+	// Implementations represents a mapping of contract implementations
+	// to chain by L1 chain id, combined for all superchain targets on the same L1 chain.
+	Implementations map[uint64]ContractImplementations
+
+	// SuperchainSemver represents a mapping of contract name to desired semver version.
+	SuperchainSemver ContractVersions
+
+	// fsys is the filesystem the registry was loaded from,
+	// used to lazily load the larger genesis and bytecode data.
+	fsys fs.FS
+
+	// index holds the lookups by path, name, L1 chain and address, built at the end of Load.
+	index *registryIndex
+}
+
+// Load reads a Registry from the given filesystem.
+// The filesystem is expected to follow the layout of this module directory:
+// a semver.yaml file, and the configs, extra and implementations directories.
+func Load(fsys fs.FS) (*Registry, error) {
+	r := &Registry{
+		Superchains:          map[string]*Superchain{},
+		OPChains:             map[uint64]*ChainConfig{},
+		Addresses:            map[uint64]*AddressList{},
+		GenesisSystemConfigs: map[uint64]*GenesisSystemConfig{},
+		Implementations:      map[uint64]ContractImplementations{},
+		fsys:                 fsys,
+	}
+
+	var err error
+	r.SuperchainSemver, err = loadContractVersions(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read semver.yaml: %w", err)
+	}
+
+	superchainTargets, err := fs.ReadDir(fsys, "configs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read superchain dir: %w", err)
+	}
+	// iterate over superchain-target entries
+	for _, s := range superchainTargets {
+		if !s.IsDir() {
+			continue // ignore files, e.g. a readme
+		}
+		// Load superchain-target config
+		superchainConfigData, err := fs.ReadFile(fsys, path.Join("configs", s.Name(), "superchain.yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read superchain config: %w", err)
+		}
+		var superchainEntry Superchain
+		if err := yaml.Unmarshal(superchainConfigData, &superchainEntry.Config); err != nil {
+			return nil, fmt.Errorf("failed to decode superchain config: %w", err)
+		}
+		superchainEntry.Superchain = s.Name()
+		if err := superchainEntry.Config.Hardforks.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid hardforks of superchain config %s: %w", s.Name(), err)
+		}
+		superchainEntry.Config.RollupParameters = superchainEntry.Config.RollupParameters.withDefaults(DefaultRollupParameters())
+		if err := superchainEntry.Config.RollupParameters.Check(); err != nil {
+			return nil, fmt.Errorf("invalid superchain config %s: %w", s.Name(), err)
+		}
+
+		// iterate over the chains of this superchain-target
+		chainEntries, err := fs.ReadDir(fsys, path.Join("configs", s.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read superchain dir: %w", err)
+		}
+		for _, c := range chainEntries {
+			if c.IsDir() || !strings.HasSuffix(c.Name(), ".yaml") {
+				continue // ignore files. Chains must be a directory of configs.
+			}
+			if c.Name() == "superchain.yaml" {
+				continue // already processed
+			}
+			// load chain config
+			chainConfigData, err := fs.ReadFile(fsys, path.Join("configs", s.Name(), c.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read superchain config %s/%s: %w", s.Name(), c.Name(), err)
+			}
+			var chainConfig ChainConfig
+			if err := yaml.Unmarshal(chainConfigData, &chainConfig); err != nil {
+				return nil, fmt.Errorf("failed to decode chain config %s/%s: %w", s.Name(), c.Name(), err)
+			}
+			chainConfig.Chain = strings.TrimSuffix(c.Name(), ".yaml")
+
+			jsonName := chainConfig.Chain + ".json"
+			addressesData, err := fs.ReadFile(fsys, path.Join("extra", "addresses", s.Name(), jsonName))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read addresses data of chain %s/%s: %w", s.Name(), jsonName, err)
+			}
+			var addrs AddressList
+			if err := json.Unmarshal(addressesData, &addrs); err != nil {
+				return nil, fmt.Errorf("failed to decode addresses %s/%s: %w", s.Name(), jsonName, err)
+			}
+			if addrs.SystemConfigProxy == (Address{}) {
+				addrs.SystemConfigProxy = chainConfig.SystemConfigAddr
+			} else if addrs.SystemConfigProxy != chainConfig.SystemConfigAddr {
+				return nil, fmt.Errorf("SystemConfigProxy %s of %s/%s does not match system_config_addr %s of the chain config",
+					addrs.SystemConfigProxy, s.Name(), jsonName, chainConfig.SystemConfigAddr)
+			}
+
+			if chainConfig.DepositContractAddr == nil {
+				portal := addrs.OptimismPortalProxy
+				chainConfig.DepositContractAddr = &portal
+			} else if *chainConfig.DepositContractAddr != addrs.OptimismPortalProxy {
+				return nil, fmt.Errorf("OptimismPortalProxy %s of %s/%s does not match deposit_contract_addr %s of the chain config",
+					addrs.OptimismPortalProxy, s.Name(), jsonName, *chainConfig.DepositContractAddr)
+			}
+			if err := chainConfig.applyHardforks(superchainEntry.Config.Hardforks); err != nil {
+				return nil, fmt.Errorf("invalid hardforks of chain config %s/%s: %w", s.Name(), c.Name(), err)
+			}
+			chainConfig.RollupParameters = chainConfig.RollupParameters.withDefaults(superchainEntry.Config.RollupParameters)
+			if err := chainConfig.RollupParameters.Check(); err != nil {
+				return nil, fmt.Errorf("invalid chain config %s/%s: %w", s.Name(), c.Name(), err)
+			}
+
+			genesisSysCfgData, err := fs.ReadFile(fsys, path.Join("extra", "genesis-system-configs", s.Name(), jsonName))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read genesis system config data of chain %s/%s: %w", s.Name(), jsonName, err)
+			}
+			var genesisSysCfg GenesisSystemConfig
+			if err := json.Unmarshal(genesisSysCfgData, &genesisSysCfg); err != nil {
+				return nil, fmt.Errorf("failed to decode genesis system config %s/%s: %w", s.Name(), jsonName, err)
+			}
+
+			chainConfig.Superchain = s.Name()
+			if other, ok := r.OPChains[chainConfig.ChainID]; ok {
+				return nil, fmt.Errorf("found chain config %q in superchain target %q with chain ID %d "+
+					"conflicts with chain %q in superchain %q and chain ID %d",
+					chainConfig.Name, chainConfig.Superchain, chainConfig.ChainID,
+					other.Name, other.Superchain, other.ChainID)
+			}
+			superchainEntry.ChainIDs = append(superchainEntry.ChainIDs, chainConfig.ChainID)
+			r.OPChains[chainConfig.ChainID] = &chainConfig
+			r.Addresses[chainConfig.ChainID] = &addrs
+			r.GenesisSystemConfigs[chainConfig.ChainID] = &genesisSysCfg
+		}
+
+		implementations, err := loadContractImplementations(fsys, s.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read implementations of superchain target %s: %w", s.Name(), err)
+		}
+		superchainEntry.Implementations = implementations
+
+		r.Superchains[superchainEntry.Superchain] = &superchainEntry
+
+		// Superchain targets may share the same L1 chain, e.g. a testnet and its devnets.
+		// All their implementations are deployed on the same L1, so they are combined,
+		// as long as no version resolves to different addresses.
+		l1ChainID := superchainEntry.Config.L1.ChainID
+		if l1Implementations, ok := r.Implementations[l1ChainID]; ok {
+			if err := l1Implementations.mergeChecked(implementations); err != nil {
+				return nil, fmt.Errorf("implementations of superchain target %s conflict with other targets on L1 chain %d: %w",
+					s.Name(), l1ChainID, err)
+			}
+		} else {
+			r.Implementations[l1ChainID] = implementations.Copy()
+		}
+	}
+	r.index = newRegistryIndex(r)
+	return r, nil
+}
+
+// ImplementationsFor returns the contract implementations of the given superchain target.
+func (r *Registry) ImplementationsFor(target string) (ContractImplementations, error) {
+	return implementationsFor(r.Superchains, target)
+}
+
+func implementationsFor(superchains map[string]*Superchain, target string) (ContractImplementations, error) {
+	sc, ok := superchains[target]
+	if !ok {
+		return ContractImplementations{}, fmt.Errorf("%w %q", ErrUnknownSuperchain, target)
+	}
+	return sc.Implementations, nil
+}
+
+// LoadGenesis loads the genesis definition of the given chain.
+func (r *Registry) LoadGenesis(chainID uint64) (*Genesis, error) {
+	return loadGenesis(r.fsys, r.OPChains, chainID)
+}
+
+// LoadContractBytecode loads the contract bytecode with the given code hash.
+func (r *Registry) LoadContractBytecode(codeHash Hash) ([]byte, error) {
+	return loadContractBytecode(r.fsys, codeHash)
+}
+
+func loadGenesis(fsys fs.FS, chains map[uint64]*ChainConfig, chainID uint64) (*Genesis, error) {
+	ch, ok := chains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrUnknownChain, chainID)
+	}
+	f, err := fsys.Open(path.Join("extra", "genesis", ch.Superchain, ch.Chain+".json.gz"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chain genesis definition of %d: %w", chainID, err)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to open gzip reader of genesis data of %d: %w", ErrCorruptData, chainID, err)
+	}
+	defer r.Close()
+	var out Genesis
+	if err := json.NewDecoder(r).Decode(&out); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode genesis allocation of %d: %w", ErrCorruptData, chainID, err)
+	}
+	return &out, nil
+}
+
+func loadContractBytecode(fsys fs.FS, codeHash Hash) ([]byte, error) {
+	f, err := fsys.Open(path.Join("extra", "bytecodes", codeHash.String()+".bin.gz"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w %s: %w", ErrMissingBytecode, codeHash, err)
+		}
+		return nil, fmt.Errorf("failed to open bytecode %s: %w", codeHash, err)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to open gzip reader of bytecode %s: %w", ErrCorruptData, codeHash, err)
+	}
+	defer r.Close()
+	code, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read bytecode %s: %w", ErrCorruptData, codeHash, err)
+	}
+	return code, nil
+}
+
+// unionFS combines multiple filesystems that share the same root into one.
+// Files are opened from the first filesystem that has them.
+type unionFS []fs.FS
+
+func (u unionFS) Open(name string) (fs.File, error) {
+	for _, fsys := range u {
+		f, err := fsys.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
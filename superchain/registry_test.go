@@ -0,0 +1,262 @@
+package superchain
+
+import (
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// TestLoadEmbedded asserts that loading the embedded registry data
+// produces the same data as the package-level globals.
+func TestLoadEmbedded(t *testing.T) {
+	reg, err := Load(embeddedFS)
+	if err != nil {
+		t.Fatalf("failed to load embedded registry: %v", err)
+	}
+	if len(reg.Superchains) != len(Superchains) {
+		t.Errorf("got %d superchains, expected %d", len(reg.Superchains), len(Superchains))
+	}
+	if len(reg.OPChains) != len(OPChains) {
+		t.Errorf("got %d chains, expected %d", len(reg.OPChains), len(OPChains))
+	}
+	for id, ch := range OPChains {
+		other, ok := reg.OPChains[id]
+		if !ok {
+			t.Fatalf("chain %d is missing", id)
+		}
+		if other.Superchain != ch.Superchain || other.Chain != ch.Chain {
+			t.Errorf("chain %d is %s/%s, expected %s/%s", id, other.Superchain, other.Chain, ch.Superchain, ch.Chain)
+		}
+		if *reg.Addresses[id] != *Addresses[id] {
+			t.Errorf("chain %d has different addresses", id)
+		}
+		if *reg.GenesisSystemConfigs[id] != *GenesisSystemConfigs[id] {
+			t.Errorf("chain %d has different genesis system config", id)
+		}
+		if _, err := reg.LoadGenesis(id); err != nil {
+			t.Errorf("failed to load genesis of chain %d: %v", id, err)
+		}
+	}
+	if reg.SuperchainSemver != SuperchainSemver {
+		t.Errorf("got different superchain semver")
+	}
+}
+
+// TestLoadErrors asserts that malformed registry data results in an error, not a panic.
+func TestLoadErrors(t *testing.T) {
+	semver, err := fs.ReadFile(embeddedFS, "semver.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	impls, err := fs.ReadFile(embeddedFS, "implementations/implementations.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := func() fstest.MapFS {
+		return fstest.MapFS{
+			"semver.yaml":                              {Data: semver},
+			"implementations/implementations.yaml":     {Data: impls},
+			"implementations/networks/test.yaml":       {Data: []byte("")},
+			"configs/test/superchain.yaml":             {Data: []byte("name: Test\nl1:\n  chain_id: 1\n")},
+			"configs/test/a.yaml":                      {Data: []byte("name: A\nchain_id: 123\n")},
+			"extra/addresses/test/a.json":              {Data: []byte("{}")},
+			"extra/genesis-system-configs/test/a.json": {Data: []byte("{}")},
+		}
+	}
+
+	reg, err := Load(valid())
+	if err != nil {
+		t.Fatalf("failed to load valid registry: %v", err)
+	}
+	if reg.OPChains[123] == nil || reg.OPChains[123].Superchain != "test" {
+		t.Fatal("expected chain 123 in superchain test")
+	}
+
+	cases := []struct {
+		name   string
+		modify func(m fstest.MapFS)
+	}{
+		{"missing-semver", func(m fstest.MapFS) { delete(m, "semver.yaml") }},
+		{"invalid-semver", func(m fstest.MapFS) { m["semver.yaml"] = &fstest.MapFile{Data: []byte("system_config: foo")} }},
+		{"bad-superchain-yaml", func(m fstest.MapFS) { m["configs/test/superchain.yaml"] = &fstest.MapFile{Data: []byte("l1: [")} }},
+		{"bad-chain-yaml", func(m fstest.MapFS) { m["configs/test/a.yaml"] = &fstest.MapFile{Data: []byte("chain_id: foo")} }},
+		{"missing-addresses", func(m fstest.MapFS) { delete(m, "extra/addresses/test/a.json") }},
+		{"unknown-address", func(m fstest.MapFS) {
+			m["extra/addresses/test/a.json"] = &fstest.MapFile{Data: []byte(`{"Unknown": "0x0000000000000000000000000000000000000001"}`)}
+		}},
+		{"mismatched-system-config", func(m fstest.MapFS) {
+			m["extra/addresses/test/a.json"] = &fstest.MapFile{Data: []byte(`{"SystemConfigProxy": "0x0000000000000000000000000000000000000001"}`)}
+		}},
+		{"bad-genesis-system-config", func(m fstest.MapFS) {
+			m["extra/genesis-system-configs/test/a.json"] = &fstest.MapFile{Data: []byte("{")}
+		}},
+		{"mismatched-deposit-contract", func(m fstest.MapFS) {
+			m["configs/test/a.yaml"] = &fstest.MapFile{Data: []byte("name: A\nchain_id: 123\ndeposit_contract_addr: \"0x0000000000000000000000000000000000000001\"\n")}
+		}},
+		{"zero-superchain-block-time", func(m fstest.MapFS) {
+			m["configs/test/superchain.yaml"] = &fstest.MapFile{Data: []byte("name: Test\nl1:\n  chain_id: 1\nblock_time: 0\n")}
+		}},
+		{"zero-chain-channel-timeout", func(m fstest.MapFS) {
+			m["configs/test/a.yaml"] = &fstest.MapFile{Data: []byte("name: A\nchain_id: 123\nchannel_timeout: 0\n")}
+		}},
+		{"fork-override-before-genesis", func(m fstest.MapFS) {
+			m["configs/test/a.yaml"] = &fstest.MapFile{Data: []byte("name: A\nchain_id: 123\ngenesis:\n  l2_time: 100\ncanyon_time: 50\n")}
+		}},
+		{"unordered-fork-override", func(m fstest.MapFS) {
+			m["configs/test/superchain.yaml"] = &fstest.MapFile{Data: []byte("name: Test\nl1:\n  chain_id: 1\ncanyon_time: 100\ndelta_time: 200\n")}
+			m["configs/test/a.yaml"] = &fstest.MapFile{Data: []byte("name: A\nchain_id: 123\ncanyon_time: 300\n")}
+		}},
+		{"unknown-fork", func(m fstest.MapFS) {
+			m["configs/test/a.yaml"] = &fstest.MapFile{Data: []byte("name: A\nchain_id: 123\ngranite_time: 300\n")}
+		}},
+		{"missing-network-implementations", func(m fstest.MapFS) { delete(m, "implementations/networks/test.yaml") }},
+		{"conflicting-chain-id", func(m fstest.MapFS) {
+			m["configs/test/b.yaml"] = &fstest.MapFile{Data: []byte("name: B\nchain_id: 123\n")}
+			m["extra/addresses/test/b.json"] = &fstest.MapFile{Data: []byte("{}")}
+			m["extra/genesis-system-configs/test/b.json"] = &fstest.MapFile{Data: []byte("{}")}
+		}},
+	}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			m := valid()
+			test.modify(m)
+			if _, err := Load(m); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+// TestLoadRollupParameters asserts that chains inherit the rollup parameters of their superchain target,
+// which default to the OP Stack defaults, unless the chain config overrides them.
+func TestLoadRollupParameters(t *testing.T) {
+	semver, err := fs.ReadFile(embeddedFS, "semver.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	impls, err := fs.ReadFile(embeddedFS, "implementations/implementations.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := Load(fstest.MapFS{
+		"semver.yaml":                              {Data: semver},
+		"implementations/implementations.yaml":     {Data: impls},
+		"implementations/networks/test.yaml":       {Data: []byte("")},
+		"configs/test/superchain.yaml":             {Data: []byte("name: Test\nl1:\n  chain_id: 1\nseq_window_size: 100\n")},
+		"configs/test/a.yaml":                      {Data: []byte("name: A\nchain_id: 123\nblock_time: 1\nregolith_time: 10\n")},
+		"configs/test/b.yaml":                      {Data: []byte("name: B\nchain_id: 456\n")},
+		"extra/addresses/test/a.json":              {Data: []byte(`{"OptimismPortalProxy": "0x0000000000000000000000000000000000000001"}`)},
+		"extra/addresses/test/b.json":              {Data: []byte("{}")},
+		"extra/genesis-system-configs/test/a.json": {Data: []byte("{}")},
+		"extra/genesis-system-configs/test/b.json": {Data: []byte("{}")},
+	})
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	defaults := DefaultRollupParameters()
+	sc := reg.Superchains["test"].Config
+	if *sc.SequencerWindowSize != 100 || *sc.BlockTime != *defaults.BlockTime {
+		t.Errorf("unexpected superchain rollup parameters: block time %d, sequencer window %d", *sc.BlockTime, *sc.SequencerWindowSize)
+	}
+	a, b := reg.OPChains[123], reg.OPChains[456]
+	if *a.BlockTime != 1 || *a.SequencerWindowSize != 100 || *a.ChannelTimeout != *defaults.ChannelTimeout || a.Hardforks[Regolith] != 10 {
+		t.Errorf("unexpected rollup parameters of chain A")
+	}
+	if *b.BlockTime != *defaults.BlockTime || *b.MaxSequencerDrift != *defaults.MaxSequencerDrift || b.Hardforks[Regolith] != 0 {
+		t.Errorf("unexpected rollup parameters of chain B")
+	}
+	if *a.DepositContractAddr != HexToAddress("0x0000000000000000000000000000000000000001") {
+		t.Errorf("deposit contract of chain A does not default to its OptimismPortalProxy")
+	}
+}
+
+// TestLoadHardforkOverrides asserts that the fork schedule of a chain
+// is that of its superchain target, with the overrides of the chain applied.
+func TestLoadHardforkOverrides(t *testing.T) {
+	semver, err := fs.ReadFile(embeddedFS, "semver.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	impls, err := fs.ReadFile(embeddedFS, "implementations/implementations.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := Load(fstest.MapFS{
+		"semver.yaml":                              {Data: semver},
+		"implementations/implementations.yaml":     {Data: impls},
+		"implementations/networks/test.yaml":       {Data: []byte("")},
+		"configs/test/superchain.yaml":             {Data: []byte("name: Test\nl1:\n  chain_id: 1\ncanyon_time: 100\ndelta_time: 200\n")},
+		"configs/test/a.yaml":                      {Data: []byte("name: A\nchain_id: 123\ngenesis:\n  l2_time: 10\ndelta_time: 150\necotone_time: 300\n")},
+		"configs/test/b.yaml":                      {Data: []byte("name: B\nchain_id: 456\n")},
+		"configs/test/c.yaml":                      {Data: []byte("name: C\nchain_id: 789\ngenesis:\n  l2_time: 10\nregolith_time: 0\ncanyon_time: 0\n")},
+		"extra/addresses/test/a.json":              {Data: []byte("{}")},
+		"extra/addresses/test/b.json":              {Data: []byte("{}")},
+		"extra/addresses/test/c.json":              {Data: []byte("{}")},
+		"extra/genesis-system-configs/test/a.json": {Data: []byte("{}")},
+		"extra/genesis-system-configs/test/b.json": {Data: []byte("{}")},
+		"extra/genesis-system-configs/test/c.json": {Data: []byte("{}")},
+	})
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	a, b := reg.OPChains[123], reg.OPChains[456]
+	if !reflect.DeepEqual(a.HardforkOverrides, HardforkTable{Delta: 150, Ecotone: 300}) {
+		t.Errorf("unexpected overrides of chain A: %v", a.HardforkOverrides)
+	}
+	if !reflect.DeepEqual(a.Hardforks, HardforkTable{Regolith: 0, Canyon: 100, Delta: 150, Ecotone: 300}) {
+		t.Errorf("unexpected hardforks of chain A: %v", a.Hardforks)
+	}
+	if !reflect.DeepEqual(b.Hardforks, reg.Superchains["test"].Config.Hardforks) {
+		t.Errorf("chain B without overrides should have the superchain hardforks: %v", b.Hardforks)
+	}
+	// Time 0 activates a fork at genesis, it is not an override before the L2 genesis.
+	if c := reg.OPChains[789]; !reflect.DeepEqual(c.Hardforks, HardforkTable{Regolith: 0, Canyon: 0, Delta: 200}) {
+		t.Errorf("unexpected hardforks of chain C: %v", c.Hardforks)
+	}
+	if err := reg.OPChains[789].CheckForkSchedule(); err != nil {
+		t.Errorf("unexpected fork schedule error of chain C: %v", err)
+	}
+	if _, ok := reg.Superchains["test"].Config.Hardforks[Ecotone]; ok {
+		t.Error("chain override leaked into the superchain hardforks")
+	}
+}
+
+// TestLoadSharedL1Conflict asserts that superchain targets on the same L1 chain
+// cannot resolve the same contract version to different addresses.
+func TestLoadSharedL1Conflict(t *testing.T) {
+	semver, err := fs.ReadFile(embeddedFS, "semver.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	impls, err := fs.ReadFile(embeddedFS, "implementations/implementations.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := func(b string) fstest.MapFS {
+		return fstest.MapFS{
+			"semver.yaml":                          {Data: semver},
+			"implementations/implementations.yaml": {Data: impls},
+			"implementations/networks/a.yaml":      {Data: []byte("system_config:\n  0.1.0: \"0x0000000000000000000000000000000000000001\"\n")},
+			"implementations/networks/b.yaml":      {Data: []byte(b)},
+			"configs/a/superchain.yaml":            {Data: []byte("name: A\nl1:\n  chain_id: 1\n")},
+			"configs/b/superchain.yaml":            {Data: []byte("name: B\nl1:\n  chain_id: 1\n")},
+		}
+	}
+
+	reg, err := Load(registry("system_config:\n  0.2.0: \"0x0000000000000000000000000000000000000002\"\n"))
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	if reg.Superchains["a"].Implementations.SystemConfig.Get("0.2.0") != (Address{}) {
+		t.Fatal("implementations of b leaked into a")
+	}
+	if reg.Implementations[1].SystemConfig.Get("0.1.0") == (Address{}) || reg.Implementations[1].SystemConfig.Get("0.2.0") == (Address{}) {
+		t.Fatal("expected combined implementations for L1 chain")
+	}
+
+	_, err = Load(registry("system_config:\n  0.1.0: \"0x0000000000000000000000000000000000000002\"\n"))
+	if err == nil {
+		t.Fatal("expected conflict between superchain targets on the same L1")
+	}
+}
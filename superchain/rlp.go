@@ -0,0 +1,58 @@
+package superchain
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// Minimal RLP encoding, sufficient to hash block headers and state tries.
+// This avoids a dependency on the go-ethereum rlp package.
+
+// rlpBytes encodes a byte string.
+func rlpBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return []byte{b[0]}
+	}
+	return append(rlpHeader(0x80, len(b)), b...)
+}
+
+// rlpList encodes a list of already encoded items.
+func rlpList(items ...[]byte) []byte {
+	size := 0
+	for _, item := range items {
+		size += len(item)
+	}
+	out := rlpHeader(0xc0, size)
+	for _, item := range items {
+		out = append(out, item...)
+	}
+	return out
+}
+
+func rlpHeader(offset byte, size int) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+	sizeBytes := trimLeadingZeros(binary.BigEndian.AppendUint64(nil, uint64(size)))
+	return append([]byte{offset + 55 + byte(len(sizeBytes))}, sizeBytes...)
+}
+
+// rlpUint encodes an unsigned integer, without leading zero bytes.
+func rlpUint(v uint64) []byte {
+	return rlpBytes(trimLeadingZeros(binary.BigEndian.AppendUint64(nil, v)))
+}
+
+// rlpBig encodes a non-negative big integer. A nil integer is encoded as zero.
+func rlpBig(v *big.Int) []byte {
+	if v == nil {
+		return rlpBytes(nil)
+	}
+	return rlpBytes(v.Bytes())
+}
+
+func trimLeadingZeros(b []byte) []byte {
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
@@ -0,0 +1,33 @@
+package superchain
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestRLP(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"empty", rlpBytes(nil), "80"},
+		{"byte", rlpBytes([]byte{0x0f}), "0f"},
+		{"high-byte", rlpBytes([]byte{0x80}), "8180"},
+		{"string", rlpBytes([]byte("dog")), "83646f67"},
+		{"long-string", rlpBytes([]byte(strings.Repeat("a", 56))), "b838" + strings.Repeat("61", 56)},
+		{"empty-list", rlpList(), "c0"},
+		{"list", rlpList(rlpBytes([]byte("cat")), rlpBytes([]byte("dog"))), "c88363617483646f67"},
+		{"zero", rlpUint(0), "80"},
+		{"uint", rlpUint(1024), "820400"},
+		{"big", rlpBig(big.NewInt(1_000_000_000)), "843b9aca00"},
+		{"nil-big", rlpBig(nil), "80"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(tt.data); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package superchain
+
+import "fmt"
+
+// RollupGenesis is the genesis section of the op-node rollup config.
+type RollupGenesis struct {
+	L1           BlockID             `json:"l1"`
+	L2           BlockID             `json:"l2"`
+	L2Time       uint64              `json:"l2_time"`
+	SystemConfig GenesisSystemConfig `json:"system_config"`
+}
+
+// RollupConfig is the rollup config of a chain, in the rollup.json format of op-node.
+type RollupConfig struct {
+	Genesis RollupGenesis `json:"genesis"`
+
+	BlockTime         uint64 `json:"block_time"`
+	MaxSequencerDrift uint64 `json:"max_sequencer_drift"`
+	SeqWindowSize     uint64 `json:"seq_window_size"`
+	ChannelTimeout    uint64 `json:"channel_timeout"`
+
+	L1ChainID uint64 `json:"l1_chain_id"`
+	L2ChainID uint64 `json:"l2_chain_id"`
+
+	RegolithTime *uint64 `json:"regolith_time,omitempty"`
+	CanyonTime   *uint64 `json:"canyon_time,omitempty"`
+	DeltaTime    *uint64 `json:"delta_time,omitempty"`
+	EcotoneTime  *uint64 `json:"ecotone_time,omitempty"`
+	FjordTime    *uint64 `json:"fjord_time,omitempty"`
+
+	BatchInboxAddress       Address  `json:"batch_inbox_address"`
+	DepositContractAddress  Address  `json:"deposit_contract_address"`
+	L1SystemConfigAddress   Address  `json:"l1_system_config_address"`
+	ProtocolVersionsAddress *Address `json:"protocol_versions_address,omitempty"`
+}
+
+// LoadRollupConfig returns the op-node rollup config of the chain.
+func LoadRollupConfig(chainID uint64) (*RollupConfig, error) {
+	return rollupConfig(Superchains, OPChains, GenesisSystemConfigs, chainID)
+}
+
+// LoadRollupConfig is like the package-level LoadRollupConfig, but uses the chains of the registry.
+func (r *Registry) LoadRollupConfig(chainID uint64) (*RollupConfig, error) {
+	return rollupConfig(r.Superchains, r.OPChains, r.GenesisSystemConfigs, chainID)
+}
+
+func rollupConfig(superchains map[string]*Superchain, chains map[uint64]*ChainConfig,
+	sysConfigs map[uint64]*GenesisSystemConfig, chainID uint64,
+) (*RollupConfig, error) {
+	chain, ok := chains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrUnknownChain, chainID)
+	}
+	sc, ok := superchains[chain.Superchain]
+	if !ok {
+		return nil, fmt.Errorf("%w %q of chain %d", ErrUnknownSuperchain, chain.Superchain, chainID)
+	}
+	sysConfig, ok := sysConfigs[chainID]
+	if !ok {
+		return nil, fmt.Errorf("no genesis system config of chain %d", chainID)
+	}
+	return &RollupConfig{
+		Genesis: RollupGenesis{
+			L1:           chain.Genesis.L1,
+			L2:           chain.Genesis.L2,
+			L2Time:       chain.Genesis.L2Time,
+			SystemConfig: *sysConfig,
+		},
+		BlockTime:               *chain.BlockTime,
+		MaxSequencerDrift:       *chain.MaxSequencerDrift,
+		SeqWindowSize:           *chain.SequencerWindowSize,
+		ChannelTimeout:          *chain.ChannelTimeout,
+		L1ChainID:               sc.Config.L1.ChainID,
+		L2ChainID:               chainID,
+		RegolithTime:            chain.Hardforks.timePtr(Regolith),
+		CanyonTime:              chain.Hardforks.timePtr(Canyon),
+		DeltaTime:               chain.Hardforks.timePtr(Delta),
+		EcotoneTime:             chain.Hardforks.timePtr(Ecotone),
+		FjordTime:               chain.Hardforks.timePtr(Fjord),
+		BatchInboxAddress:       chain.BatchInboxAddr,
+		DepositContractAddress:  *chain.DepositContractAddr,
+		L1SystemConfigAddress:   chain.SystemConfigAddr,
+		ProtocolVersionsAddress: sc.Config.ProtocolVersionsAddr,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package superchain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLoadRollupConfig(t *testing.T) {
+	for id := range OPChains {
+		if _, err := LoadRollupConfig(id); err != nil {
+			t.Errorf("chain %d: %v", id, err)
+		}
+	}
+	if _, err := LoadRollupConfig(4242); err == nil {
+		t.Error("expected error for unknown chain")
+	}
+
+	cfg, err := LoadRollupConfig(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{
+		`"l2":{"hash":"0xdbf6a80fef073de06add9b0d14026d6e5a86c85f6d102c36d3d8e9cf89c2afd3","number":105235063}`,
+		`"l2_time":1686068903`,
+		`"system_config":{"batcherAddr":"0x6887246668a3b87f54deb3b94ba47a6f63f32985"`,
+		`"block_time":2`,
+		`"max_sequencer_drift":600`,
+		`"seq_window_size":3600`,
+		`"channel_timeout":300`,
+		`"l1_chain_id":1,"l2_chain_id":10`,
+		`"regolith_time":0`,
+		`"canyon_time":1704992401`,
+		`"batch_inbox_address":"0xff00000000000000000000000000000000000010"`,
+		`"deposit_contract_address":"0xbeb5fc579115071764c7423a4f12edde41f106ed"`,
+		`"l1_system_config_address":"0x229047fed2591dbec1ef1118d64f7af3db9eb290"`,
+		`"protocol_versions_address":"0x8062abc286f5e7d9428a0ccb9abd71e50d93b935"`,
+	} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("expected %s in rollup config %s", field, data)
+		}
+	}
+
+	goerli, err := LoadRollupConfig(420)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *goerli.RegolithTime != 1679079600 {
+		t.Errorf("unexpected Regolith time %d of OP Goerli", *goerli.RegolithTime)
+	}
+}
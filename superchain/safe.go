@@ -0,0 +1,186 @@
+package superchain
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain/internal/abi"
+)
+
+var (
+	// upgradeSelector is the selector of ProxyAdmin.upgrade(address,address)
+	upgradeSelector = keccak256([]byte("upgrade(address,address)"))
+	// upgradeAndCallSelector is the selector of ProxyAdmin.upgradeAndCall(address,address,bytes)
+	upgradeAndCallSelector = keccak256([]byte("upgradeAndCall(address,address,bytes)"))
+)
+
+// EncodeUpgradeCalldata returns the ABI-encoded calldata of ProxyAdmin.upgrade(proxy, implementation).
+func EncodeUpgradeCalldata(proxy Address, implementation Address) []byte {
+	out := append([]byte{}, upgradeSelector[:4]...)
+	out = append(out, abi.Word(proxy[:])...)
+	out = append(out, abi.Word(implementation[:])...)
+	return out
+}
+
+// EncodeUpgradeAndCallCalldata returns the ABI-encoded calldata of
+// ProxyAdmin.upgradeAndCall(proxy, implementation, data).
+func EncodeUpgradeAndCallCalldata(proxy Address, implementation Address, data []byte) []byte {
+	out := append([]byte{}, upgradeAndCallSelector[:4]...)
+	out = append(out, abi.Word(proxy[:])...)
+	out = append(out, abi.Word(implementation[:])...)
+	out = append(out, abi.Uint(3*32)...) // offset of the dynamic bytes argument
+	out = append(out, abi.Uint(uint64(len(data)))...)
+	out = append(out, data...)
+	if rem := len(data) % 32; rem != 0 {
+		out = append(out, make([]byte, 32-rem)...)
+	}
+	return out
+}
+
+// SafeBatch is a batch of transactions in the Safe Transaction Builder JSON format.
+type SafeBatch struct {
+	Version      string            `json:"version"`
+	ChainID      string            `json:"chainId"`
+	CreatedAt    uint64            `json:"createdAt"`
+	Meta         SafeBatchMeta     `json:"meta"`
+	Transactions []SafeTransaction `json:"transactions"`
+}
+
+type SafeBatchMeta struct {
+	Name                   string  `json:"name"`
+	Description            string  `json:"description"`
+	TxBuilderVersion       string  `json:"txBuilderVersion"`
+	CreatedFromSafeAddress Address `json:"createdFromSafeAddress"`
+}
+
+type SafeTransaction struct {
+	To                   Address           `json:"to"`
+	Value                string            `json:"value"`
+	Data                 HexBytes          `json:"data"`
+	ContractMethod       SafeMethod        `json:"contractMethod"`
+	ContractInputsValues map[string]string `json:"contractInputsValues"`
+}
+
+type SafeMethod struct {
+	Inputs  []SafeMethodInput `json:"inputs"`
+	Name    string            `json:"name"`
+	Payable bool              `json:"payable"`
+}
+
+type SafeMethodInput struct {
+	InternalType string `json:"internalType"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+}
+
+var (
+	upgradeMethod = SafeMethod{
+		Inputs: []SafeMethodInput{
+			{InternalType: "address payable", Name: "_proxy", Type: "address"},
+			{InternalType: "address", Name: "_implementation", Type: "address"},
+		},
+		Name: "upgrade",
+	}
+	upgradeAndCallMethod = SafeMethod{
+		Inputs: []SafeMethodInput{
+			{InternalType: "address payable", Name: "_proxy", Type: "address"},
+			{InternalType: "address", Name: "_implementation", Type: "address"},
+			{InternalType: "bytes", Name: "_data", Type: "bytes"},
+		},
+		Name:    "upgradeAndCall",
+		Payable: true,
+	}
+)
+
+// NewSafeBatch packages the upgrades of the plan as ProxyAdmin calls, to be executed by the ProxyAdminOwner Safe.
+// initCalldata optionally maps contract names of the plan to calldata that is called on the proxy after
+// the upgrade, using ProxyAdmin.upgradeAndCall.
+func NewSafeBatch(plan *UpgradePlan, initCalldata map[string]HexBytes) (*SafeBatch, error) {
+	return newSafeBatch(Superchains, OPChains, Addresses, plan, initCalldata)
+}
+
+// SafeBatch is like NewSafeBatch, but uses the chains and addresses of the registry.
+func (r *Registry) SafeBatch(plan *UpgradePlan, initCalldata map[string]HexBytes) (*SafeBatch, error) {
+	return newSafeBatch(r.Superchains, r.OPChains, r.Addresses, plan, initCalldata)
+}
+
+func newSafeBatch(superchains map[string]*Superchain, chains map[uint64]*ChainConfig, addresses map[uint64]*AddressList,
+	plan *UpgradePlan, initCalldata map[string]HexBytes,
+) (*SafeBatch, error) {
+	chain, ok := chains[plan.ChainID]
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrUnknownChain, plan.ChainID)
+	}
+	sc, ok := superchains[chain.Superchain]
+	if !ok {
+		return nil, fmt.Errorf("%w %q of chain %d", ErrUnknownSuperchain, chain.Superchain, plan.ChainID)
+	}
+	addrs, ok := addresses[plan.ChainID]
+	if !ok {
+		return nil, fmt.Errorf("no addresses for chain %d", plan.ChainID)
+	}
+	if addrs.ProxyAdmin == (Address{}) {
+		return nil, fmt.Errorf("no ProxyAdmin known for chain %d", plan.ChainID)
+	}
+	if addrs.ProxyAdminOwner == (Address{}) {
+		return nil, fmt.Errorf("no ProxyAdminOwner known for chain %d", plan.ChainID)
+	}
+	for name := range initCalldata {
+		found := false
+		for _, step := range plan.Steps {
+			found = found || step.Contract == name
+		}
+		if !found {
+			return nil, fmt.Errorf("init calldata for unknown contract %q", name)
+		}
+	}
+
+	batch := &SafeBatch{
+		Version: "1.0",
+		ChainID: strconv.FormatUint(sc.Config.L1.ChainID, 10),
+		Meta: SafeBatchMeta{
+			Name:                   fmt.Sprintf("Upgrade %s", chain.Name),
+			Description:            fmt.Sprintf("Upgrade the L1 contracts of chain %d through ProxyAdmin %s", plan.ChainID, addrs.ProxyAdmin),
+			TxBuilderVersion:       "1.16.1",
+			CreatedFromSafeAddress: addrs.ProxyAdminOwner,
+		},
+		Transactions: []SafeTransaction{},
+	}
+	for _, step := range plan.Steps {
+		switch step.Action {
+		case UpgradeNoop:
+			// A contract that is not upgraded is not initialized again, its init calldata would be dropped.
+			if _, ok := initCalldata[step.Contract]; ok {
+				return nil, fmt.Errorf("init calldata for %s, which is already at %s and is not upgraded",
+					step.Contract, step.Target.Version)
+			}
+			continue
+		case UpgradeDowngrade:
+			return nil, fmt.Errorf("unsupported downgrade of %s from %s to %s", step.Contract, step.Current.Version, step.Target.Version)
+		case UpgradeUpgrade:
+		default:
+			return nil, fmt.Errorf("unknown upgrade action %q of %s", step.Action, step.Contract)
+		}
+		if step.Proxy == (Address{}) || step.Target.Address == (Address{}) {
+			return nil, fmt.Errorf("missing proxy or implementation address of %s", step.Contract)
+		}
+		tx := SafeTransaction{
+			To:    addrs.ProxyAdmin,
+			Value: "0",
+			ContractInputsValues: map[string]string{
+				"_proxy":          step.Proxy.String(),
+				"_implementation": step.Target.Address.String(),
+			},
+		}
+		if data, ok := initCalldata[step.Contract]; ok && len(data) > 0 {
+			tx.Data = EncodeUpgradeAndCallCalldata(step.Proxy, step.Target.Address, data)
+			tx.ContractMethod = upgradeAndCallMethod
+			tx.ContractInputsValues["_data"] = data.String()
+		} else {
+			tx.Data = EncodeUpgradeCalldata(step.Proxy, step.Target.Address)
+			tx.ContractMethod = upgradeMethod
+		}
+		batch.Transactions = append(batch.Transactions, tx)
+	}
+	return batch, nil
+}
@@ -0,0 +1,78 @@
+package superchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncodeUpgradeCalldata(t *testing.T) {
+	proxy := HexToAddress("0x25ace71c97B33Cc4729CF772ae268934F7ab5fA1")
+	impl := HexToAddress("0xDa2332D0a7608919Cd331B1304Cd179129a90495")
+
+	expected, _ := hex.DecodeString("99a88ec4" +
+		"00000000000000000000000025ace71c97b33cc4729cf772ae268934f7ab5fa1" +
+		"000000000000000000000000da2332d0a7608919cd331b1304cd179129a90495")
+	if got := EncodeUpgradeCalldata(proxy, impl); !bytes.Equal(got, expected) {
+		t.Fatalf("wrong upgrade calldata: %x", got)
+	}
+
+	expected, _ = hex.DecodeString("9623609d" +
+		"00000000000000000000000025ace71c97b33cc4729cf772ae268934f7ab5fa1" +
+		"000000000000000000000000da2332d0a7608919cd331b1304cd179129a90495" +
+		"0000000000000000000000000000000000000000000000000000000000000060" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"abcdef0000000000000000000000000000000000000000000000000000000000")
+	if got := EncodeUpgradeAndCallCalldata(proxy, impl, []byte{0xab, 0xcd, 0xef}); !bytes.Equal(got, expected) {
+		t.Fatalf("wrong upgradeAndCall calldata: %x", got)
+	}
+}
+
+func TestNewSafeBatch(t *testing.T) {
+	deployed := SuperchainSemver
+	deployed.L1CrossDomainMessenger = "1.6.0"
+	deployed.OptimismPortal = "1.9.0"
+	plan, err := NewUpgradePlan(10, deployed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, err := NewSafeBatch(plan, map[string]HexBytes{"OptimismPortal": {0x01}})
+	if err != nil {
+		t.Fatalf("failed to create safe batch: %v", err)
+	}
+	if batch.ChainID != "1" {
+		t.Errorf("expected L1 chain ID 1, got %s", batch.ChainID)
+	}
+	if batch.Meta.CreatedFromSafeAddress != Addresses[10].ProxyAdminOwner {
+		t.Errorf("expected batch for ProxyAdminOwner, got %s", batch.Meta.CreatedFromSafeAddress)
+	}
+	if len(batch.Transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(batch.Transactions))
+	}
+	for _, tx := range batch.Transactions {
+		if tx.To != Addresses[10].ProxyAdmin {
+			t.Errorf("transaction is not sent to the ProxyAdmin: %s", tx.To)
+		}
+	}
+	if batch.Transactions[0].ContractMethod.Name != "upgrade" {
+		t.Errorf("expected upgrade, got %s", batch.Transactions[0].ContractMethod.Name)
+	}
+	if batch.Transactions[1].ContractMethod.Name != "upgradeAndCall" {
+		t.Errorf("expected upgradeAndCall, got %s", batch.Transactions[1].ContractMethod.Name)
+	}
+
+	if _, err := NewSafeBatch(plan, map[string]HexBytes{"Unknown": {0x01}}); err == nil {
+		t.Error("expected error for init calldata of unknown contract")
+	}
+	if _, err := NewSafeBatch(plan, map[string]HexBytes{"SystemConfig": {0x01}}); err == nil {
+		t.Error("expected error for init calldata of a contract that is not upgraded")
+	}
+	deployed.OptimismPortal = "2.0.0"
+	plan, err = NewUpgradePlan(10, deployed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSafeBatch(plan, nil); err == nil {
+		t.Error("expected error for downgrade")
+	}
+}
@@ -1,11 +1,11 @@
 package superchain
 
 import (
-	"compress/gzip"
+	"bytes"
 	"embed"
 	"encoding/json"
 	"fmt"
-	"io"
+	"io/fs"
 	"path"
 	"reflect"
 	"strings"
@@ -27,9 +27,12 @@ var implementationsFS embed.FS
 //go:embed semver.yaml
 var semverFS embed.FS
 
+// embeddedFS combines all embedded registry data, rooted at the module directory.
+var embeddedFS fs.FS = unionFS{superchainFS, extraFS, implementationsFS, semverFS}
+
 type BlockID struct {
-	Hash   Hash   `yaml:"hash"`
-	Number uint64 `yaml:"number"`
+	Hash   Hash   `yaml:"hash" json:"hash"`
+	Number uint64 `yaml:"number" json:"number"`
 }
 
 type ChainGenesis struct {
@@ -37,6 +40,10 @@ type ChainGenesis struct {
 	L2        BlockID   `yaml:"l2"`
 	L2Time    uint64    `yaml:"l2_time"`
 	ExtraData *HexBytes `yaml:"extra_data,omitempty"`
+	// LegacyHash is the hash of the genesis block of a chain that was migrated to Bedrock.
+	// The genesis definition of these chains is the legacy genesis block, while L2 is the Bedrock transition block.
+	// It is unset for chains that started on Bedrock.
+	LegacyHash *Hash `yaml:"legacy_hash,omitempty"`
 }
 
 type ChainConfig struct {
@@ -51,6 +58,33 @@ type ChainConfig struct {
 
 	Genesis ChainGenesis `yaml:"genesis"`
 
+	// DepositContractAddr is the OptimismPortalProxy of the chain.
+	// It is optional, and defaults to the OptimismPortalProxy of the chain addresses.
+	DepositContractAddr *Address `yaml:"deposit_contract_addr,omitempty"`
+
+	// BerlinBlock is the L2 block that activated the Berlin upgrade.
+	// It is optional, and only set for chains that did not activate Berlin at genesis, e.g. OP Mainnet.
+	BerlinBlock *uint64 `yaml:"berlin_block,omitempty"`
+
+	// EIP1559Elasticity, EIP1559Denominator and EIP1559DenominatorCanyon are the EIP-1559 parameters of op-geth.
+	// They are optional, and default to the parameters of OP Stack chains, see ExportGenesis.
+	EIP1559Elasticity        *uint64 `yaml:"eip1559_elasticity,omitempty"`
+	EIP1559Denominator       *uint64 `yaml:"eip1559_denominator,omitempty"`
+	EIP1559DenominatorCanyon *uint64 `yaml:"eip1559_denominator_canyon,omitempty"`
+
+	// RollupParameters override the rollup parameters of the superchain target.
+	// After loading, all parameters are set.
+	RollupParameters `yaml:",inline"`
+
+	// HardforkOverrides are the fork activation times of the chain that differ from its superchain target,
+	// configured with a "<fork>_time" key per fork, like the superchain config.
+	HardforkOverrides HardforkTable `yaml:"-"`
+
+	// Hardforks is the effective fork schedule of the chain:
+	// the hardforks of its superchain target, with the HardforkOverrides of the chain applied.
+	// It is set when the chain is loaded.
+	Hardforks HardforkTable `yaml:"-"`
+
 	// Superchain is a simple string to identify the superchain.
 	// This is implied by directory structure, and not encoded in the config file itself.
 	Superchain string `yaml:"-"`
@@ -59,8 +93,56 @@ type ChainConfig struct {
 	Chain string `yaml:"-"`
 }
 
-// AddressList represents the set of network specific contracts for a given network.
+func (c *ChainConfig) UnmarshalYAML(value *yaml.Node) error {
+	type chainConfig ChainConfig
+	if err := value.Decode((*chainConfig)(c)); err != nil {
+		return err
+	}
+	overrides, err := decodeHardforks(value)
+	if err != nil {
+		return err
+	}
+	c.HardforkOverrides = overrides
+	return nil
+}
+
+// MarshalYAML encodes the config with the HardforkOverrides under their "<fork>_time" keys, like UnmarshalYAML decodes them.
+func (c ChainConfig) MarshalYAML() (any, error) {
+	type chainConfig ChainConfig
+	return encodeHardforks(chainConfig(c), c.HardforkOverrides, nil)
+}
+
+// applyHardforks sets the effective fork schedule of the chain, from the superchain hardforks and the chain overrides.
+// Overrides must not activate a fork before the L2 genesis of the chain,
+// except for time 0, which activates the fork at genesis, like in CheckForkSchedule.
+func (c *ChainConfig) applyHardforks(superchain HardforkTable) error {
+	hardforks := maps.Clone(superchain)
+	if hardforks == nil {
+		hardforks = HardforkTable{}
+	}
+	for fork, t := range c.HardforkOverrides {
+		if t != 0 && t < c.Genesis.L2Time {
+			return fmt.Errorf("fork %s override at %d activates before the L2 genesis at %d", fork, t, c.Genesis.L2Time)
+		}
+		hardforks[fork] = t
+	}
+	if err := hardforks.Validate(); err != nil {
+		return err
+	}
+	c.Hardforks = hardforks
+	return nil
+}
+
+// AddressList represents the set of network specific contracts and roles for a given network.
+// The JSON encoding is flat, e.g. {"ProxyAdmin": "0x...", "Guardian": "0x..."},
+// and decoding rejects unknown keys.
 type AddressList struct {
+	ProtocolContracts
+	PrivilegedRoles
+}
+
+// ProtocolContracts represents the set of L1 contracts of a network.
+type ProtocolContracts struct {
 	AddressManager                    Address `json:"AddressManager"`
 	L1CrossDomainMessengerProxy       Address `json:"L1CrossDomainMessengerProxy"`
 	L1ERC721BridgeProxy               Address `json:"L1ERC721BridgeProxy"`
@@ -69,6 +151,25 @@ type AddressList struct {
 	OptimismMintableERC20FactoryProxy Address `json:"OptimismMintableERC20FactoryProxy"`
 	OptimismPortalProxy               Address `json:"OptimismPortalProxy"`
 	ProxyAdmin                        Address `json:"ProxyAdmin"`
+	// SystemConfigProxy always equals the SystemConfigAddr of the chain config.
+	// It is set to that address if the addresses data does not include it.
+	SystemConfigProxy Address `json:"SystemConfigProxy"`
+}
+
+// PrivilegedRoles represents the set of accounts with privileged roles in the L1 contracts
+// of a network. These are not known for every network, in which case they are unset.
+type PrivilegedRoles struct {
+	ProxyAdminOwner   Address `json:"ProxyAdminOwner"`
+	SystemConfigOwner Address `json:"SystemConfigOwner"`
+	Guardian          Address `json:"Guardian"`
+	Challenger        Address `json:"Challenger"`
+}
+
+func (a *AddressList) UnmarshalJSON(data []byte) error {
+	type addressList AddressList // without methods, to not recurse into UnmarshalJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*addressList)(a))
 }
 
 // ImplementationList represents the set of implementation contracts to be used together
@@ -130,6 +231,48 @@ func (a AddressSet) Versions() []string {
 	return keys
 }
 
+// Version returns the semantic version of the given address in the set,
+// with a "v" prefix. It returns false if the address is not in the set.
+// If the address is listed under multiple versions, the lowest version is returned.
+func (a AddressSet) Version(addr Address) (string, bool) {
+	for _, version := range a.Versions() {
+		if a.Get(version) == addr {
+			return version, true
+		}
+	}
+	return "", false
+}
+
+// Lookup returns the name of the contract and the semantic version
+// of the given implementation address, e.g. "OptimismPortal" and "v1.10.0".
+// It returns false if the address is not a known implementation.
+func (c ContractImplementations) Lookup(addr Address) (contract string, version string, ok bool) {
+	for _, s := range c.namedSets() {
+		if version, ok := s.set.Version(addr); ok {
+			return s.contract, version, true
+		}
+	}
+	return "", "", false
+}
+
+type namedAddressSet struct {
+	contract string
+	set      AddressSet
+}
+
+// namedSets returns the address sets of all contracts, with their contract name.
+func (c ContractImplementations) namedSets() []namedAddressSet {
+	return []namedAddressSet{
+		{"L1CrossDomainMessenger", c.L1CrossDomainMessenger},
+		{"L1ERC721Bridge", c.L1ERC721Bridge},
+		{"L1StandardBridge", c.L1StandardBridge},
+		{"L2OutputOracle", c.L2OutputOracle},
+		{"OptimismMintableERC20Factory", c.OptimismMintableERC20Factory},
+		{"OptimismPortal", c.OptimismPortal},
+		{"SystemConfig", c.SystemConfig},
+	}
+}
+
 // Resolve will return a set of addresses that resolve a given
 // semantic version set.
 func (c ContractImplementations) Resolve(versions ContractVersions) (ImplementationList, error) {
@@ -159,10 +302,13 @@ func (c ContractImplementations) Resolve(versions ContractVersions) (Implementat
 	return implementations, nil
 }
 
-// resolve returns a VersionedContract that matches the passed in semver version
-// given a set of addresses.
+// resolve returns a VersionedContract with the highest version in the set of
+// addresses that satisfies the given semver constraint.
 func resolve(set AddressSet, version string) (VersionedContract, error) {
-	version = canonicalizeSemver(version)
+	constraint, err := ParseConstraint(version)
+	if err != nil {
+		return VersionedContract{}, err
+	}
 
 	var out VersionedContract
 	keys := set.Versions()
@@ -170,27 +316,25 @@ func resolve(set AddressSet, version string) (VersionedContract, error) {
 		return out, fmt.Errorf("no implementations found")
 	}
 
+	// keys are sorted, so the last match is the highest version
 	for _, k := range keys {
-		res := semver.Compare(k, version)
-		if res >= 0 {
+		if constraint.Matches(k) {
 			out = VersionedContract{
 				Version: k,
 				Address: set.Get(k),
 			}
-			if res == 0 {
-				break
-			}
 		}
 	}
 	if out == (VersionedContract{}) {
-		return out, fmt.Errorf("cannot resolve semver")
+		return out, fmt.Errorf("cannot resolve semver %q, available versions: %s", version, strings.Join(keys, ", "))
 	}
 	return out, nil
 }
 
 // ContractVersions represents the desired semantic version of the contracts
 // in the superchain. This currently only supports L1 contracts but could
-// represent L2 predeploys in the future.
+// represent L2 predeploys in the future. Each version may be a semver
+// constraint, see Constraint.
 type ContractVersions struct {
 	L1CrossDomainMessenger       string `yaml:"l1_cross_domain_messenger"`
 	L1ERC721Bridge               string `yaml:"l1_erc721_bridge"`
@@ -201,7 +345,7 @@ type ContractVersions struct {
 	SystemConfig                 string `yaml:"system_config"`
 }
 
-// Check will sanity check the validity of the semantic version strings
+// Check will sanity check the validity of the semantic version constraints
 // in the ContractVersions struct.
 func (c ContractVersions) Check() error {
 	val := reflect.ValueOf(c)
@@ -214,9 +358,8 @@ func (c ContractVersions) Check() error {
 		if str == "" {
 			return fmt.Errorf("empty version for field %s", val.Type().Field(i).Name)
 		}
-		str = canonicalizeSemver(str)
-		if !semver.IsValid(str) {
-			return fmt.Errorf("invalid semver %s for field %s", str, val.Type().Field(i).Name)
+		if _, err := ParseConstraint(str); err != nil {
+			return fmt.Errorf("invalid semver %s for field %s: %w", str, val.Type().Field(i).Name, err)
 		}
 	}
 	return nil
@@ -228,8 +371,14 @@ func (c ContractVersions) Check() error {
 // because the global implementations were deployed with create2 and therefore should
 // be on every network.
 func newContractImplementations(network string) (ContractImplementations, error) {
+	return loadContractImplementations(embeddedFS, network)
+}
+
+// loadContractImplementations is like newContractImplementations,
+// but reads the implementations from the given filesystem.
+func loadContractImplementations(fsys fs.FS, network string) (ContractImplementations, error) {
 	var globals ContractImplementations
-	globalData, err := implementationsFS.ReadFile(path.Join("implementations", "implementations.yaml"))
+	globalData, err := fs.ReadFile(fsys, path.Join("implementations", "implementations.yaml"))
 	if err != nil {
 		return globals, fmt.Errorf("failed to read implementations: %w", err)
 	}
@@ -243,7 +392,7 @@ func newContractImplementations(network string) (ContractImplementations, error)
 
 	filepath := path.Join("implementations", "networks", network+".yaml")
 	var impls ContractImplementations
-	data, err := implementationsFS.ReadFile(filepath)
+	data, err := fs.ReadFile(fsys, filepath)
 	if err != nil {
 		return impls, fmt.Errorf("failed to read implementations: %w", err)
 	}
@@ -307,6 +456,45 @@ func (c ContractImplementations) Merge(other ContractImplementations) {
 	copySemverMap(c.SystemConfig, other.SystemConfig)
 }
 
+// mergeChecked is like Merge, but returns an error instead of overwriting
+// a version that is already set to a different address.
+func (c ContractImplementations) mergeChecked(other ContractImplementations) error {
+	if err := mergeAddressSetChecked(c.L1CrossDomainMessenger, other.L1CrossDomainMessenger); err != nil {
+		return fmt.Errorf("L1CrossDomainMessenger: %w", err)
+	}
+	if err := mergeAddressSetChecked(c.L1ERC721Bridge, other.L1ERC721Bridge); err != nil {
+		return fmt.Errorf("L1ERC721Bridge: %w", err)
+	}
+	if err := mergeAddressSetChecked(c.L1StandardBridge, other.L1StandardBridge); err != nil {
+		return fmt.Errorf("L1StandardBridge: %w", err)
+	}
+	if err := mergeAddressSetChecked(c.L2OutputOracle, other.L2OutputOracle); err != nil {
+		return fmt.Errorf("L2OutputOracle: %w", err)
+	}
+	if err := mergeAddressSetChecked(c.OptimismMintableERC20Factory, other.OptimismMintableERC20Factory); err != nil {
+		return fmt.Errorf("OptimismMintableERC20Factory: %w", err)
+	}
+	if err := mergeAddressSetChecked(c.OptimismPortal, other.OptimismPortal); err != nil {
+		return fmt.Errorf("OptimismPortal: %w", err)
+	}
+	if err := mergeAddressSetChecked(c.SystemConfig, other.SystemConfig); err != nil {
+		return fmt.Errorf("SystemConfig: %w", err)
+	}
+	return nil
+}
+
+// mergeAddressSetChecked copies all versions of src into dst,
+// and errors if a version is already set to a different address.
+func mergeAddressSetChecked(dst, src AddressSet) error {
+	for version, addr := range src {
+		if existing := dst.Get(version); existing != (Address{}) && existing != addr {
+			return fmt.Errorf("version %s is both %s and %s", version, existing, addr)
+		}
+		dst[version] = addr
+	}
+	return nil
+}
+
 // Copy will return a shallow copy of the ContractImplementations.
 func (c ContractImplementations) Copy() ContractImplementations {
 	return ContractImplementations{
@@ -331,22 +519,29 @@ type GenesisAccount struct {
 	CodeHash Hash          `json:"codeHash,omitempty"` // code hash only, to reduce overhead of duplicate bytecode
 	Storage  map[Hash]Hash `json:"storage,omitempty"`
 	Balance  *HexBig       `json:"balance,omitempty"`
-	Nonce    uint64        `json:"nonce,omitempty"`
+	Nonce    HexUint64     `json:"nonce,omitempty"`
 }
 
+// Genesis is the genesis definition of a chain.
+// All numeric fields are encoded as hex quantities, and the extra data as hex bytes.
+// Decoding also accepts the legacy encoding, with decimal numbers and base64 extra data.
 type Genesis struct {
 	// Block properties
-	Nonce      uint64  `json:"nonce"`
-	Timestamp  uint64  `json:"timestamp"`
-	ExtraData  []byte  `json:"extraData"`
-	GasLimit   uint64  `json:"gasLimit"`
-	Difficulty *HexBig `json:"difficulty"`
-	Mixhash    Hash    `json:"mixHash"`
-	Coinbase   Address `json:"coinbase"`
-	Number     uint64  `json:"number"`
-	GasUsed    uint64  `json:"gasUsed"`
-	ParentHash Hash    `json:"parentHash"`
-	BaseFee    *HexBig `json:"baseFeePerGas"`
+	Nonce      HexUint64 `json:"nonce"`
+	Timestamp  HexUint64 `json:"timestamp"`
+	ExtraData  HexBytes  `json:"extraData"`
+	GasLimit   HexUint64 `json:"gasLimit"`
+	Difficulty *HexBig   `json:"difficulty"`
+	Mixhash    Hash      `json:"mixHash"`
+	Coinbase   Address   `json:"coinbase"`
+	Number     HexUint64 `json:"number"`
+	GasUsed    HexUint64 `json:"gasUsed"`
+	ParentHash Hash      `json:"parentHash"`
+	BaseFee    *HexBig   `json:"baseFeePerGas"`
+	// ExcessBlobGas and BlobGasUsed are only part of the header if Ecotone (Cancun) is active at genesis,
+	// and default to 0.
+	ExcessBlobGas *HexUint64 `json:"excessBlobGas,omitempty"`
+	BlobGasUsed   *HexUint64 `json:"blobGasUsed,omitempty"`
 	// State data
 	Alloc map[Address]GenesisAccount `json:"alloc"`
 	// StateHash substitutes for a full embedded state allocation,
@@ -356,6 +551,87 @@ type Genesis struct {
 	// The chain-config is not included. This is derived from the chain and superchain definition instead.
 }
 
+func (g *Genesis) UnmarshalJSON(data []byte) error {
+	type genesis Genesis
+	var dec struct {
+		*genesis
+		ExtraData json.RawMessage `json:"extraData"`
+	}
+	dec.genesis = (*genesis)(g)
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	extraData, err := unmarshalLegacyBytes(dec.ExtraData)
+	if err != nil {
+		return fmt.Errorf("invalid extraData: %w", err)
+	}
+	g.ExtraData = extraData
+	return nil
+}
+
+// RollupParameters are the rollup protocol parameters of a chain.
+// All parameters are optional in the configs: unset chain parameters default to those
+// of the superchain target, and unset superchain parameters default to DefaultRollupParameters.
+type RollupParameters struct {
+	// BlockTime is the L2 block time, in seconds.
+	BlockTime *uint64 `yaml:"block_time,omitempty"`
+	// SequencerWindowSize is the number of L1 blocks in which batches of an L1 origin must be included.
+	SequencerWindowSize *uint64 `yaml:"seq_window_size,omitempty"`
+	// ChannelTimeout is the number of L1 blocks in which a channel must be completed.
+	ChannelTimeout *uint64 `yaml:"channel_timeout,omitempty"`
+	// MaxSequencerDrift is the maximum number of seconds that an L2 block may be ahead of its L1 origin.
+	MaxSequencerDrift *uint64 `yaml:"max_sequencer_drift,omitempty"`
+}
+
+// DefaultRollupParameters returns the rollup parameters of OP Stack chains,
+// as hardcoded in op-node for chains of the registry.
+func DefaultRollupParameters() RollupParameters {
+	return RollupParameters{
+		BlockTime:           u64ptr(2),
+		SequencerWindowSize: u64ptr(3600),
+		ChannelTimeout:      u64ptr(300),
+		MaxSequencerDrift:   u64ptr(600),
+	}
+}
+
+// withDefaults returns the parameters, with unset parameters taken from the defaults.
+func (p RollupParameters) withDefaults(defaults RollupParameters) RollupParameters {
+	if p.BlockTime == nil {
+		p.BlockTime = defaults.BlockTime
+	}
+	if p.SequencerWindowSize == nil {
+		p.SequencerWindowSize = defaults.SequencerWindowSize
+	}
+	if p.ChannelTimeout == nil {
+		p.ChannelTimeout = defaults.ChannelTimeout
+	}
+	if p.MaxSequencerDrift == nil {
+		p.MaxSequencerDrift = defaults.MaxSequencerDrift
+	}
+	return p
+}
+
+// Check returns an error if a parameter is unset or zero.
+func (p RollupParameters) Check() error {
+	for _, param := range []struct {
+		name  string
+		value *uint64
+	}{
+		{"block_time", p.BlockTime},
+		{"seq_window_size", p.SequencerWindowSize},
+		{"channel_timeout", p.ChannelTimeout},
+		{"max_sequencer_drift", p.MaxSequencerDrift},
+	} {
+		if param.value == nil {
+			return fmt.Errorf("rollup parameter %s is not set", param.name)
+		}
+		if *param.value == 0 {
+			return fmt.Errorf("rollup parameter %s must not be zero", param.name)
+		}
+	}
+	return nil
+}
+
 type SuperchainL1Info struct {
 	ChainID   uint64 `yaml:"chain_id"`
 	PublicRPC string `yaml:"public_rpc"`
@@ -369,11 +645,52 @@ type SuperchainConfig struct {
 	ProtocolVersionsAddr *Address `yaml:"protocol_versions_addr,omitempty"`
 	SuperchainConfigAddr *Address `yaml:"superchain_config_addr,omitempty"`
 
-	// Hardfork Configuration
-	CanyonTime  *uint64 `yaml:"canyon_time,omitempty"`
-	DeltaTime   *uint64 `yaml:"delta_time,omitempty"`
-	EclipseTime *uint64 `yaml:"eclipse_time,omitempty"`
-	FjordTime   *uint64 `yaml:"fjord_time,omitempty"`
+	// Hardforks are the activation times of the forks of the superchain target,
+	// configured with a "<fork>_time" key per fork, e.g. canyon_time.
+	// Regolith defaults to 0: all chains, except for a few early testnets, activated Regolith at genesis.
+	Hardforks HardforkTable `yaml:"-"`
+
+	// Deprecated: use Hardforks.
+	CanyonTime *uint64 `yaml:"-"`
+	// Deprecated: use Hardforks.
+	DeltaTime *uint64 `yaml:"-"`
+	// Deprecated: Eclipse was renamed to Ecotone, use Hardforks.
+	EclipseTime *uint64 `yaml:"-"`
+	// Deprecated: use Hardforks.
+	FjordTime *uint64 `yaml:"-"`
+
+	// RollupParameters are the defaults of the chains of the superchain target.
+	// After loading, all parameters are set.
+	RollupParameters `yaml:",inline"`
+}
+
+func (c *SuperchainConfig) UnmarshalYAML(value *yaml.Node) error {
+	type superchainConfig SuperchainConfig
+	if err := value.Decode((*superchainConfig)(c)); err != nil {
+		return err
+	}
+	hardforks, err := decodeHardforks(value)
+	if err != nil {
+		return err
+	}
+	if _, ok := hardforks[Regolith]; !ok {
+		hardforks[Regolith] = 0
+	}
+	c.Hardforks = hardforks
+	c.CanyonTime = hardforks.timePtr(Canyon)
+	c.DeltaTime = hardforks.timePtr(Delta)
+	c.EclipseTime = hardforks.timePtr(Ecotone)
+	c.FjordTime = hardforks.timePtr(Fjord)
+	return nil
+}
+
+// MarshalYAML encodes the config with the hardforks under their "<fork>_time" keys, like UnmarshalYAML decodes them.
+// Regolith is left out if it activates at 0, its default.
+func (c SuperchainConfig) MarshalYAML() (any, error) {
+	type superchainConfig SuperchainConfig
+	return encodeHardforks(superchainConfig(c), c.Hardforks, func(fork Fork, t uint64) bool {
+		return fork == Regolith && t == 0
+	})
 }
 
 type Superchain struct {
@@ -384,6 +701,9 @@ type Superchain struct {
 
 	// Superchain identifier, without capitalization or display changes.
 	Superchain string
+
+	// Implementations are the contract implementations available to this superchain target.
+	Implementations ContractImplementations
 }
 
 var Superchains = map[string]*Superchain{}
@@ -395,110 +715,51 @@ var Addresses = map[uint64]*AddressList{}
 var GenesisSystemConfigs = map[uint64]*GenesisSystemConfig{}
 
 // Implementations represents a global mapping of contract implementations
-// to chain by chain id.
+// to chain by L1 chain id. Superchain targets that share the same L1 chain
+// have their implementations combined, see ImplementationsFor to get the
+// implementations of a specific superchain target.
 var Implementations = map[uint64]ContractImplementations{}
 
 // SuperchainSemver represents a global mapping of contract name to desired semver version.
 var SuperchainSemver ContractVersions
 
-func init() {
-	var err error
-	SuperchainSemver, err = newContractVersions()
-	if err != nil {
-		panic(fmt.Errorf("failed to read semver.yaml: %w", err))
-	}
+// globalFS is the filesystem that the package-level globals were loaded from.
+var globalFS = embeddedFS
 
-	superchainTargets, err := superchainFS.ReadDir("configs")
+// globalIndex is the index of the registry that the package-level globals were loaded from.
+var globalIndex *registryIndex
+
+func init() {
+	reg, err := Load(embeddedFS)
 	if err != nil {
-		panic(fmt.Errorf("failed to read superchain dir: %w", err))
+		panic(err)
 	}
-	// iterate over superchain-target entries
-	for _, s := range superchainTargets {
-		if !s.IsDir() {
-			continue // ignore files, e.g. a readme
-		}
-		// Load superchain-target config
-		superchainConfigData, err := superchainFS.ReadFile(path.Join("configs", s.Name(), "superchain.yaml"))
-		if err != nil {
-			panic(fmt.Errorf("failed to read superchain config: %w", err))
-		}
-		var superchainEntry Superchain
-		if err := yaml.Unmarshal(superchainConfigData, &superchainEntry.Config); err != nil {
-			panic(fmt.Errorf("failed to decode superchain config: %w", err))
-		}
-		superchainEntry.Superchain = s.Name()
-
-		// iterate over the chains of this superchain-target
-		chainEntries, err := superchainFS.ReadDir(path.Join("configs", s.Name()))
-		if err != nil {
-			panic(fmt.Errorf("failed to read superchain dir: %w", err))
-		}
-		for _, c := range chainEntries {
-			if c.IsDir() || !strings.HasSuffix(c.Name(), ".yaml") {
-				continue // ignore files. Chains must be a directory of configs.
-			}
-			if c.Name() == "superchain.yaml" {
-				continue // already processed
-			}
-			// load chain config
-			chainConfigData, err := superchainFS.ReadFile(path.Join("configs", s.Name(), c.Name()))
-			if err != nil {
-				panic(fmt.Errorf("failed to read superchain config %s/%s: %w", s.Name(), c.Name(), err))
-			}
-			var chainConfig ChainConfig
-			if err := yaml.Unmarshal(chainConfigData, &chainConfig); err != nil {
-				panic(fmt.Errorf("failed to decode chain config %s/%s: %w", s.Name(), c.Name(), err))
-			}
-			chainConfig.Chain = strings.TrimSuffix(c.Name(), ".yaml")
-
-			jsonName := chainConfig.Chain + ".json"
-			addressesData, err := extraFS.ReadFile(path.Join("extra", "addresses", s.Name(), jsonName))
-			if err != nil {
-				panic(fmt.Errorf("failed to read addresses data of chain %s/%s: %w", s.Name(), jsonName, err))
-			}
-			var addrs AddressList
-			if err := json.Unmarshal(addressesData, &addrs); err != nil {
-				panic(fmt.Errorf("failed to decode addresses %s/%s: %w", s.Name(), jsonName, err))
-			}
-
-			genesisSysCfgData, err := extraFS.ReadFile(path.Join("extra", "genesis-system-configs", s.Name(), jsonName))
-			if err != nil {
-				panic(fmt.Errorf("failed to read genesis system config data of chain %s/%s: %w", s.Name(), jsonName, err))
-			}
-			var genesisSysCfg GenesisSystemConfig
-			if err := json.Unmarshal(genesisSysCfgData, &genesisSysCfg); err != nil {
-				panic(fmt.Errorf("failed to decode genesis system config %s/%s: %w", s.Name(), jsonName, err))
-			}
-
-			chainConfig.Superchain = s.Name()
-			if other, ok := OPChains[chainConfig.ChainID]; ok {
-				panic(fmt.Errorf("found chain config %q in superchain target %q with chain ID %d "+
-					"conflicts with chain %q in superchain %q and chain ID %d",
-					chainConfig.Name, chainConfig.Superchain, chainConfig.ChainID,
-					other.Name, other.Superchain, other.ChainID))
-			}
-			superchainEntry.ChainIDs = append(superchainEntry.ChainIDs, chainConfig.ChainID)
-			OPChains[chainConfig.ChainID] = &chainConfig
-			Addresses[chainConfig.ChainID] = &addrs
-			GenesisSystemConfigs[chainConfig.ChainID] = &genesisSysCfg
-		}
-
-		Superchains[superchainEntry.Superchain] = &superchainEntry
-
-		implementations, err := newContractImplementations(s.Name())
-		if err != nil {
-			panic(fmt.Errorf("failed to read implementations of superchain target %s: %w", s.Name(), err))
-		}
+	setGlobals(reg)
+}
 
-		Implementations[superchainEntry.Config.L1.ChainID] = implementations
-	}
+// setGlobals replaces the package-level globals with the contents of the registry.
+func setGlobals(reg *Registry) {
+	globalFS = reg.fsys
+	globalIndex = reg.index
+	SuperchainSemver = reg.SuperchainSemver
+	Superchains = reg.Superchains
+	OPChains = reg.OPChains
+	Addresses = reg.Addresses
+	GenesisSystemConfigs = reg.GenesisSystemConfigs
+	Implementations = reg.Implementations
 }
 
-// newContractVersions will read the contract versions from semver.yaml
+// newContractVersions will read the contract versions from the embedded semver.yaml
 // and check to make sure that it is valid.
 func newContractVersions() (ContractVersions, error) {
+	return loadContractVersions(embeddedFS)
+}
+
+// loadContractVersions will read the contract versions from semver.yaml
+// and check to make sure that it is valid.
+func loadContractVersions(fsys fs.FS) (ContractVersions, error) {
 	var versions ContractVersions
-	semvers, err := semverFS.ReadFile("semver.yaml")
+	semvers, err := fs.ReadFile(fsys, "semver.yaml")
 	if err != nil {
 		return versions, fmt.Errorf("failed to read semver.yaml: %w", err)
 	}
@@ -511,38 +772,15 @@ func newContractVersions() (ContractVersions, error) {
 	return versions, nil
 }
 
+// ImplementationsFor returns the contract implementations of the given superchain target.
+func ImplementationsFor(target string) (ContractImplementations, error) {
+	return implementationsFor(Superchains, target)
+}
+
 func LoadGenesis(chainID uint64) (*Genesis, error) {
-	ch, ok := OPChains[chainID]
-	if !ok {
-		return nil, fmt.Errorf("unknown chain %d", chainID)
-	}
-	f, err := extraFS.Open(path.Join("extra", "genesis", ch.Superchain, ch.Chain+".json.gz"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to open chain genesis definition of %d: %w", chainID, err)
-	}
-	defer f.Close()
-	r, err := gzip.NewReader(f)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open gzip reader of genesis data of %d: %w", chainID, err)
-	}
-	defer r.Close()
-	var out Genesis
-	if err := json.NewDecoder(r).Decode(&out); err != nil {
-		return nil, fmt.Errorf("failed to decode genesis allocation of %d: %w", chainID, err)
-	}
-	return &out, nil
+	return loadGenesis(globalFS, OPChains, chainID)
 }
 
 func LoadContractBytecode(codeHash Hash) ([]byte, error) {
-	f, err := extraFS.Open(path.Join("extra", "bytecodes", codeHash.String()+".bin.gz"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to open bytecode %s: %w", codeHash, err)
-	}
-	defer f.Close()
-	r, err := gzip.NewReader(f)
-	if err != nil {
-		return nil, fmt.Errorf("")
-	}
-	defer r.Close()
-	return io.ReadAll(r)
+	return loadContractBytecode(globalFS, codeHash)
 }
@@ -1,6 +1,9 @@
 package superchain
 
 import (
+	"bytes"
+	"encoding/json"
+	"math/big"
 	"path"
 	"strings"
 	"testing"
@@ -39,6 +42,25 @@ func TestConfigs(t *testing.T) {
 	}
 }
 
+// TestAddresses ensures that all addresses data is consistent with the chain configs.
+func TestAddresses(t *testing.T) {
+	for id, addrs := range Addresses {
+		if addrs.SystemConfigProxy != OPChains[id].SystemConfigAddr {
+			t.Errorf("chain %d has SystemConfigProxy %s but system config address %s", id, addrs.SystemConfigProxy, OPChains[id].SystemConfigAddr)
+		}
+		if addrs.ProxyAdmin == (Address{}) {
+			t.Errorf("chain %d has no ProxyAdmin", id)
+		}
+	}
+	mainnetOP := Addresses[10]
+	if mainnetOP.Guardian != HexToAddress("0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A") {
+		t.Errorf("wrong OP-Mainnet Guardian %s", mainnetOP.Guardian)
+	}
+	if mainnetOP.Challenger != HexToAddress("0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A") {
+		t.Errorf("wrong OP-Mainnet Challenger %s", mainnetOP.Challenger)
+	}
+}
+
 func TestGenesis(t *testing.T) {
 	for id := range OPChains {
 		_, err := LoadGenesis(id)
@@ -48,6 +70,52 @@ func TestGenesis(t *testing.T) {
 	}
 }
 
+// TestGenesisJSON checks that genesis definitions in both the legacy and the hex encoding decode the same,
+// and that they are encoded in the hex encoding.
+func TestGenesisJSON(t *testing.T) {
+	legacy := `{"nonce":0,"timestamp":1686789347,"extraData":"YWxsIHlvdXIgYmFzZSBhcmUgYmVsb25nIHRvIHlvdS4=","gasLimit":30000000,` +
+		`"number":0,"gasUsed":0,"alloc":{"0x4200000000000000000000000000000000000000":{"nonce":1}}}`
+	hex := `{"nonce":"0x0","timestamp":"0x648a5ce3","extraData":"0x616c6c20796f75722062617365206172652062656c6f6e6720746f20796f752e",` +
+		`"gasLimit":"0x1c9c380","number":"0x0","gasUsed":"0x0","alloc":{"0x4200000000000000000000000000000000000000":{"nonce":"0x1"}}}`
+	var a, b Genesis
+	if err := json.Unmarshal([]byte(legacy), &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(hex), &b); err != nil {
+		t.Fatal(err)
+	}
+	if a.Timestamp != 1686789347 || a.GasLimit != 30000000 || string(a.ExtraData) != "all your base are belong to you." {
+		t.Fatalf("unexpected legacy decoding: %+v", a)
+	}
+	if a.Alloc[HexToAddress("0x4200000000000000000000000000000000000000")].Nonce != 1 {
+		t.Fatal("unexpected legacy account nonce")
+	}
+	encA, err := json.Marshal(&a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encB, err := json.Marshal(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encA, encB) {
+		t.Fatalf("legacy and hex genesis encode differently:\n%s\n%s", encA, encB)
+	}
+	for _, field := range []string{`"timestamp":"0x648a5ce3"`, `"gasLimit":"0x1c9c380"`, `"extraData":"0x616c6c`, `"nonce":"0x1"`} {
+		if !strings.Contains(string(encA), field) {
+			t.Errorf("expected %s in encoding %s", field, encA)
+		}
+	}
+
+	var invalid Genesis
+	if err := json.Unmarshal([]byte(`{"gasLimit":"1c9c380"}`), &invalid); err == nil {
+		t.Error("expected error for hex quantity without 0x prefix")
+	}
+	if err := json.Unmarshal([]byte(`{"gasLimit":-1}`), &invalid); err == nil {
+		t.Error("expected error for negative quantity")
+	}
+}
+
 // TestImplementations ensures that the global Implementations
 // map is populated.
 func TestImplementations(t *testing.T) {
@@ -56,6 +124,53 @@ func TestImplementations(t *testing.T) {
 	}
 }
 
+// TestImplementationsSharedL1 ensures that superchain targets which share an L1 chain
+// keep their own implementations, and that the L1 view combines all of them.
+func TestImplementationsSharedL1(t *testing.T) {
+	l1Targets := map[uint64][]string{}
+	for name, sc := range Superchains {
+		l1Targets[sc.Config.L1.ChainID] = append(l1Targets[sc.Config.L1.ChainID], name)
+	}
+	if len(l1Targets[5]) < 2 {
+		t.Fatalf("expected multiple superchain targets on goerli, got %v", l1Targets[5])
+	}
+	for l1ChainID, targets := range l1Targets {
+		l1Impls, ok := Implementations[l1ChainID]
+		if !ok {
+			t.Fatalf("no implementations for L1 chain %d", l1ChainID)
+		}
+		for _, target := range targets {
+			impls, err := ImplementationsFor(target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for version, addr := range impls.L1CrossDomainMessenger {
+				if l1Impls.L1CrossDomainMessenger.Get(version) != addr {
+					t.Errorf("L1 chain %d is missing L1CrossDomainMessenger %s of superchain target %s", l1ChainID, version, target)
+				}
+			}
+		}
+	}
+
+	goerli, err := ImplementationsFor("goerli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	devnet, err := ImplementationsFor("goerli-dev-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goerli.L1CrossDomainMessenger.Get("1.5.1") == (Address{}) {
+		t.Fatal("expected goerli specific L1CrossDomainMessenger implementation")
+	}
+	if devnet.L1CrossDomainMessenger.Get("1.5.1") != (Address{}) {
+		t.Fatal("goerli specific implementation leaked into goerli-dev-0")
+	}
+	if _, err := ImplementationsFor("unknown"); err == nil {
+		t.Fatal("expected error for unknown superchain target")
+	}
+}
+
 // TestContractImplementations tests specific contracts implementations are set
 // correctly.
 func TestContractImplementations(t *testing.T) {
@@ -216,6 +331,64 @@ func TestResolve(t *testing.T) {
 			version: "v2.x",
 			expect:  "v2.5.1",
 		},
+		{
+			name: "caret-excludes-major",
+			set: AddressSet{
+				"v2.0.0": HexToAddress("0x456"),
+				"v1.5.0": HexToAddress("0x123"),
+				"v1.4.0": HexToAddress("0x234"),
+			},
+			version: "^1.0.0",
+			expect:  "v1.5.0",
+		},
+		{
+			name: "tilde",
+			set: AddressSet{
+				"1.6.0": HexToAddress("0x456"),
+				"1.5.3": HexToAddress("0x123"),
+				"1.5.0": HexToAddress("0x234"),
+			},
+			version: "~1.5.0",
+			expect:  "v1.5.3",
+		},
+		{
+			name: "range",
+			set: AddressSet{
+				"1.7.0": HexToAddress("0x456"),
+				"1.6.2": HexToAddress("0x123"),
+				"1.4.0": HexToAddress("0x234"),
+			},
+			version: ">=1.5.0 <1.7.0",
+			expect:  "v1.6.2",
+		},
+		{
+			name: "exact-not-highest",
+			set: AddressSet{
+				"1.7.0": HexToAddress("0x456"),
+				"1.6.0": HexToAddress("0x123"),
+			},
+			version: "1.6.0",
+			expect:  "v1.6.0",
+		},
+		{
+			name: "skip-prerelease",
+			set: AddressSet{
+				"1.7.0-beta.1": HexToAddress("0x456"),
+				"1.6.0":        HexToAddress("0x123"),
+			},
+			version: "^1.0.0",
+			expect:  "v1.6.0",
+		},
+		{
+			name: "opt-in-prerelease",
+			set: AddressSet{
+				"1.7.0-beta.2": HexToAddress("0x456"),
+				"1.7.0-beta.1": HexToAddress("0x123"),
+				"1.6.0":        HexToAddress("0x234"),
+			},
+			version: "^1.7.0-beta.1",
+			expect:  "v1.7.0-beta.2",
+		},
 	}
 
 	for _, test := range cases {
@@ -231,6 +404,27 @@ func TestResolve(t *testing.T) {
 	}
 }
 
+// TestResolveErrors ensures that unresolvable versions report the available versions.
+func TestResolveErrors(t *testing.T) {
+	set := AddressSet{
+		"v1.0.0": HexToAddress("0x123"),
+		"v1.1.0": HexToAddress("0x234"),
+	}
+	_, err := resolve(set, "^2.0.0")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "v1.0.0, v1.1.0") {
+		t.Fatalf("error does not list the available versions: %v", err)
+	}
+	if _, err := resolve(set, "^1.x.2"); err == nil {
+		t.Fatal("expected error for invalid constraint")
+	}
+	if _, err := resolve(AddressSet{}, "^1.0.0"); err == nil {
+		t.Fatal("expected error for empty set")
+	}
+}
+
 // TestAddressSet ensures that the AddressSet.Get method works with
 // both the "v" prefix and without the "v" prefix.
 func TestAddressSet(t *testing.T) {
@@ -254,6 +448,36 @@ func TestAddressSet(t *testing.T) {
 	}
 }
 
+// TestAddressSetVersionDuplicate checks that an address listed under multiple versions
+// resolves to the lowest version.
+func TestAddressSetVersionDuplicate(t *testing.T) {
+	dup := HexToAddress("0x0000000000000000000000000000000000000345")
+	set := AddressSet{
+		"v1.2.0": dup,
+		"1.0.1":  dup,
+		"1.1.0":  HexToAddress("0x0000000000000000000000000000000000000234"),
+	}
+	if v, ok := set.Version(dup); !ok || v != "v1.0.1" {
+		t.Fatalf("expected lowest version v1.0.1 of duplicate address, got %s", v)
+	}
+}
+
+// TestContractImplementationsLookup ensures that implementation addresses
+// are mapped back to their contract name and version.
+func TestContractImplementationsLookup(t *testing.T) {
+	impls, err := ImplementationsFor("mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract, version, ok := impls.Lookup(HexToAddress("0xD14AA6C7B6D92803F3910Ec1DADCCd0757341862"))
+	if !ok || contract != "OptimismPortal" || version != "v1.10.0" {
+		t.Fatalf("wrong lookup result %s %s %v", contract, version, ok)
+	}
+	if _, _, ok := impls.Lookup(HexToAddress("0x0000000000000000000000000000000000000bad")); ok {
+		t.Fatal("unexpected lookup result for unknown address")
+	}
+}
+
 // TestContractBytecodes verifies that all bytecodes can be loaded successfully,
 // and hash to the code-hash in the name.
 func TestContractBytecodes(t *testing.T) {
@@ -282,22 +506,58 @@ func TestContractBytecodes(t *testing.T) {
 	}
 }
 
-// TestCanyonTimestampOnBlockBoundary asserts that Canyon will activate on a block's timestamp.
-// This is critical because the create2Deployer only activates on a block's timestamp.
-func TestCanyonTimestampOnBlockBoundary(t *testing.T) {
-	for superchainName, superchainConfig := range Superchains {
-		if superchainConfig.Config.CanyonTime == nil {
-			continue
+// TestForkSchedules asserts that all forks activate on a block's timestamp, in order, and not before genesis.
+// This is critical because e.g. the create2Deployer only activates on a block's timestamp.
+func TestForkSchedules(t *testing.T) {
+	if err := CheckForkSchedules(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestVerifyGenesis ensures that the genesis definition of every chain
+// hashes to the L2 genesis hash of its chain config.
+func TestVerifyGenesis(t *testing.T) {
+	for id := range OPChains {
+		if err := VerifyGenesis(id); err != nil {
+			t.Errorf("chain %d: %v", id, err)
 		}
-		ct := *superchainConfig.Config.CanyonTime
-		for _, id := range superchainConfig.ChainIDs {
-			chainCfg := OPChains[id]
-			canyonOffset := ct - chainCfg.Genesis.L2Time
-			// Block time is hardcoded in op-node/rollup/superchain.go
-			if canyonOffset%2 != 0 {
-				t.Fatalf("Canyon time on superchain %v for %v is not on the block time. canyon time: %v. L2 start time: %v, block time: %v",
-					superchainName, id, ct, chainCfg.Genesis.L2Time, 2)
-			}
+	}
+}
+
+// TestGenesisHeaderFields checks the optional header fields of each fork that is active at genesis.
+func TestGenesisHeaderFields(t *testing.T) {
+	excessBlobGas := HexUint64(0x20000)
+	g := &Genesis{
+		Timestamp:     100,
+		Difficulty:    (*HexBig)(new(big.Int)),
+		BaseFee:       (*HexBig)(big.NewInt(1000000000)),
+		ExcessBlobGas: &excessBlobGas,
+	}
+	for _, tc := range []struct {
+		name      string
+		hardforks HardforkTable
+		fields    int
+	}{
+		{"bedrock", HardforkTable{Regolith: 0}, 16},
+		{"canyon", HardforkTable{Regolith: 0, Canyon: 100, Delta: 100}, 17},
+		{"ecotone", HardforkTable{Regolith: 0, Canyon: 0, Delta: 0, Ecotone: 100}, 20},
+		{"ecotone later", HardforkTable{Regolith: 0, Canyon: 0, Delta: 0, Ecotone: 101}, 17},
+	} {
+		fields := g.headerFields(tc.hardforks, emptyRootHash)
+		if len(fields) != tc.fields {
+			t.Errorf("%s: expected %d header fields, got %d", tc.name, tc.fields, len(fields))
 		}
 	}
+	ecotone := HardforkTable{Regolith: 0, Canyon: 0, Delta: 0, Ecotone: 0}
+	fields := g.headerFields(ecotone, emptyRootHash)
+	var zero Hash
+	for i, expected := range [][]byte{rlpUint(0), rlpUint(0x20000), rlpBytes(zero[:])} {
+		if got := fields[17+i]; !bytes.Equal(got, expected) {
+			t.Errorf("Ecotone header field %d is %x, expected %x", 17+i, got, expected)
+		}
+	}
+	canyon := HardforkTable{Regolith: 0, Canyon: 0}
+	if g.BlockHash(&SuperchainConfig{Hardforks: ecotone}) == g.BlockHash(&SuperchainConfig{Hardforks: canyon}) {
+		t.Error("Ecotone header fields do not change the genesis hash")
+	}
 }
@@ -0,0 +1,134 @@
+package superchain
+
+import (
+	"bytes"
+	"sort"
+)
+
+// emptyRootHash is the root of an empty Merkle-Patricia trie, keccak256(rlp("")).
+var emptyRootHash = keccak256(rlpBytes(nil))
+
+// emptyCodeHash is the code hash of accounts without code, keccak256("").
+var emptyCodeHash = keccak256(nil)
+
+// SecureTrie computes the root of a secure Merkle-Patricia trie, the trie
+// of the state and of the storage of accounts, where all keys are hashed with keccak256.
+// It only keeps the entries in memory, and computes the root hash from scratch.
+// This avoids a dependency on the go-ethereum trie package.
+type SecureTrie struct {
+	entries map[Hash][]byte
+}
+
+// NewSecureTrie returns an empty SecureTrie.
+func NewSecureTrie() *SecureTrie {
+	return &SecureTrie{entries: map[Hash][]byte{}}
+}
+
+// Update sets the value of the key. An empty value deletes the key.
+func (t *SecureTrie) Update(key, value []byte) {
+	hashed := keccak256(key)
+	if len(value) == 0 {
+		delete(t.entries, hashed)
+		return
+	}
+	t.entries[hashed] = bytes.Clone(value)
+}
+
+// Hash returns the root hash of the trie.
+func (t *SecureTrie) Hash() Hash {
+	entries := make([]trieEntry, 0, len(t.entries))
+	for k, v := range t.entries {
+		entries = append(entries, trieEntry{key: toNibbles(k[:]), value: v})
+	}
+	return trieRoot(entries)
+}
+
+type trieEntry struct {
+	key   []byte // nibbles
+	value []byte
+}
+
+// trieRoot computes the root hash of a trie with the given unique entries.
+func trieRoot(entries []trieEntry) Hash {
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+	return keccak256(trieNode(entries, 0))
+}
+
+// trieNode returns the RLP encoding of the node of the sorted entries,
+// whose keys all share the first depth nibbles.
+func trieNode(entries []trieEntry, depth int) []byte {
+	switch len(entries) {
+	case 0:
+		return rlpBytes(nil)
+	case 1:
+		return rlpList(rlpBytes(hexPrefix(entries[0].key[depth:], true)), rlpBytes(entries[0].value))
+	}
+	// Entries are sorted, so the common prefix of all keys is the common prefix of the first and last key.
+	first, last := entries[0].key, entries[len(entries)-1].key
+	prefix := 0
+	for depth+prefix < len(first) && first[depth+prefix] == last[depth+prefix] {
+		prefix++
+	}
+	if prefix > 0 {
+		return rlpList(rlpBytes(hexPrefix(first[depth:depth+prefix], false)), trieRef(trieNode(entries, depth+prefix)))
+	}
+	children := make([][]byte, 17)
+	children[16] = rlpBytes(nil)
+	start := 0
+	if len(first) == depth {
+		// The key ends at this branch, it is sorted first.
+		children[16] = rlpBytes(entries[0].value)
+		start = 1
+	}
+	for nibble := byte(0); nibble < 16; nibble++ {
+		end := start
+		for end < len(entries) && entries[end].key[depth] == nibble {
+			end++
+		}
+		if end == start {
+			children[nibble] = rlpBytes(nil)
+		} else {
+			children[nibble] = trieRef(trieNode(entries[start:end], depth+1))
+		}
+		start = end
+	}
+	return rlpList(children...)
+}
+
+// trieRef returns the reference to a node from its parent:
+// the node itself if it is shorter than a hash, or its hash otherwise.
+func trieRef(node []byte) []byte {
+	if len(node) < 32 {
+		return node
+	}
+	h := keccak256(node)
+	return rlpBytes(h[:])
+}
+
+func toNibbles(key []byte) []byte {
+	out := make([]byte, 2*len(key))
+	for i, b := range key {
+		out[2*i] = b >> 4
+		out[2*i+1] = b & 0x0f
+	}
+	return out
+}
+
+// hexPrefix encodes the nibbles of a leaf or extension node path.
+func hexPrefix(nibbles []byte, leaf bool) []byte {
+	flag := byte(0)
+	if leaf {
+		flag = 2
+	}
+	var out []byte
+	if len(nibbles)%2 == 1 {
+		out = append(out, (flag+1)<<4|nibbles[0])
+		nibbles = nibbles[1:]
+	} else {
+		out = append(out, flag<<4)
+	}
+	for i := 0; i < len(nibbles); i += 2 {
+		out = append(out, nibbles[i]<<4|nibbles[i+1])
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package superchain
+
+import (
+	"strings"
+	"testing"
+)
+
+func stringTrieRoot(kvs map[string]string) Hash {
+	var entries []trieEntry
+	for k, v := range kvs {
+		entries = append(entries, trieEntry{key: toNibbles([]byte(k)), value: []byte(v)})
+	}
+	return trieRoot(entries)
+}
+
+// TestTrieRoot checks the trie against the test vectors of the go-ethereum trie package.
+func TestTrieRoot(t *testing.T) {
+	if root := stringTrieRoot(nil); root != HexToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421") {
+		t.Fatalf("wrong empty root %s", root)
+	}
+	root := stringTrieRoot(map[string]string{
+		"doe":          "reindeer",
+		"dog":          "puppy",
+		"dogglesworth": "cat",
+	})
+	if root != HexToHash("0x8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3") {
+		t.Fatalf("wrong root %s", root)
+	}
+	root = stringTrieRoot(map[string]string{"A": strings.Repeat("a", 50)})
+	if root != HexToHash("0xd23786fb4a010da3ce639d66d5e904a11dbc02746d1ce25029e53290cabf28ab") {
+		t.Fatalf("wrong root of single long value %s", root)
+	}
+}
+
+func TestSecureTrie(t *testing.T) {
+	trie := NewSecureTrie()
+	if trie.Hash() != emptyRootHash {
+		t.Fatal("expected empty root")
+	}
+	trie.Update([]byte("key"), []byte("value"))
+	root := trie.Hash()
+	trie.Update([]byte("other"), []byte("value"))
+	if trie.Hash() == root {
+		t.Fatal("expected root to change")
+	}
+	trie.Update([]byte("other"), nil)
+	if trie.Hash() != root {
+		t.Fatal("expected deletion to restore the root")
+	}
+}
+
+// TestGenesisStateRoot checks the state roots of the genesis allocations against known good values,
+// which also match the state roots of the genesis block headers of the chains.
+func TestGenesisStateRoot(t *testing.T) {
+	tests := []struct {
+		chainID uint64
+		root    string
+	}{
+		{10, "0xeddb4c1786789419153a27c4c80ff44a2226b6eda04f7e22ce5bae892ea568eb"},        // mainnet/op, state hash only
+		{424, "0xc891504eeae643be84862387db004530d1cdd0395fe11caae0f8a46add18d743"},       // mainnet/pgn
+		{8453, "0xb2afcb88cd1d0ab228f0415d99b0fb90a18e8515daf5eb31f55b5c4697e18328"},      // mainnet/base
+		{7777777, "0x4d4dad33fe99c65bb1f368d5d28569f975cc21444ac5f404793b01a9b2d2de9a"},   // mainnet/zora
+		{58008, "0x616c0525bc0a6842e49d4bd093fd78216ff27080575bd3064d2863795c7ccc46"},     // sepolia/pgn
+		{84532, "0x907f339ca16b3e45a89a7f4cc29d4430c8d4178d73b370ec9180e04a0dd7fcf3"},     // sepolia/base
+		{11155420, "0x06787a17a3ed87c339a39dbbeeb311578a0c83ed29daa2db95da62b28efce8a9"},  // sepolia/op
+		{999999999, "0xc3f28b00d7dbd5bff9124df624716c7992d671c7da8b8d8d636d75fb92ce102b"}, // sepolia/zora
+	}
+	for _, tt := range tests {
+		g, err := LoadGenesis(tt.chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, err := g.StateRoot()
+		if err != nil {
+			t.Fatalf("chain %d: %v", tt.chainID, err)
+		}
+		if root != HexToHash(tt.root) {
+			t.Errorf("chain %d: expected state root %s, got %s", tt.chainID, tt.root, root)
+		}
+	}
+}
+
+// TestGenesisStateRootBadCode ensures that accounts with missing or mismatching code are rejected.
+func TestGenesisStateRootBadCode(t *testing.T) {
+	g, err := LoadGenesis(8453)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := HexToAddress("0x4200000000000000000000000000000000000000")
+	acc := g.Alloc[addr]
+	acc.CodeHash = keccak256([]byte("missing"))
+	g.Alloc[addr] = acc
+	if _, err := g.StateRoot(); err == nil {
+		t.Fatal("expected error for missing bytecode")
+	}
+}
@@ -0,0 +1,125 @@
+package superchain
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/semver"
+)
+
+// UpgradeAction describes what needs to happen to a proxy to reach its target implementation.
+type UpgradeAction string
+
+const (
+	// UpgradeNoop means the proxy already uses the target version.
+	UpgradeNoop UpgradeAction = "no-op"
+	// UpgradeUpgrade means the proxy needs to be upgraded to the target version.
+	UpgradeUpgrade UpgradeAction = "upgrade"
+	// UpgradeDowngrade means the deployed version is newer than the target version.
+	// Downgrades are not supported, the step needs to be resolved manually.
+	UpgradeDowngrade UpgradeAction = "unsupported-downgrade"
+)
+
+// UpgradeStep describes the upgrade of a single proxy.
+type UpgradeStep struct {
+	// Contract is the name of the implementation contract, e.g. "OptimismPortal".
+	Contract string `json:"contract"`
+	// Proxy is the address of the proxy of the contract.
+	Proxy Address `json:"proxy"`
+	// Current is the currently deployed implementation. The address is unset
+	// if the deployed version is not known to the implementations registry.
+	Current VersionedContract `json:"current"`
+	// Target is the implementation the proxy should be upgraded to.
+	Target VersionedContract `json:"target"`
+	Action UpgradeAction     `json:"action"`
+}
+
+// UpgradePlan is the ordered list of steps to upgrade the L1 contracts of a chain
+// to a set of target versions.
+type UpgradePlan struct {
+	ChainID uint64        `json:"chainId"`
+	Steps   []UpgradeStep `json:"steps"`
+}
+
+// NeedsUpgrade returns whether any of the steps upgrades a proxy.
+func (p *UpgradePlan) NeedsUpgrade() bool {
+	for _, step := range p.Steps {
+		if step.Action == UpgradeUpgrade {
+			return true
+		}
+	}
+	return false
+}
+
+// NewUpgradePlan creates the plan to upgrade the chain with the given deployed versions
+// to the versions of SuperchainSemver.
+func NewUpgradePlan(chainID uint64, deployed ContractVersions) (*UpgradePlan, error) {
+	return newUpgradePlan(Superchains, OPChains, Addresses, chainID, deployed, SuperchainSemver)
+}
+
+// UpgradePlan creates the plan to upgrade the chain with the given deployed versions
+// to the target versions.
+func (r *Registry) UpgradePlan(chainID uint64, deployed ContractVersions, target ContractVersions) (*UpgradePlan, error) {
+	return newUpgradePlan(r.Superchains, r.OPChains, r.Addresses, chainID, deployed, target)
+}
+
+func newUpgradePlan(superchains map[string]*Superchain, chains map[uint64]*ChainConfig, addresses map[uint64]*AddressList,
+	chainID uint64, deployed ContractVersions, target ContractVersions,
+) (*UpgradePlan, error) {
+	chain, ok := chains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrUnknownChain, chainID)
+	}
+	addrs, ok := addresses[chainID]
+	if !ok {
+		return nil, fmt.Errorf("no addresses for chain %d", chainID)
+	}
+	impls, err := implementationsFor(superchains, chain.Superchain)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := impls.Resolve(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target versions of chain %d: %w", chainID, err)
+	}
+
+	// The steps are ordered like the contracts in the ImplementationList.
+	entries := []struct {
+		contract string
+		proxy    Address
+		deployed string
+		set      AddressSet
+		target   VersionedContract
+	}{
+		{"L1CrossDomainMessenger", addrs.L1CrossDomainMessengerProxy, deployed.L1CrossDomainMessenger, impls.L1CrossDomainMessenger, targets.L1CrossDomainMessenger},
+		{"L1ERC721Bridge", addrs.L1ERC721BridgeProxy, deployed.L1ERC721Bridge, impls.L1ERC721Bridge, targets.L1ERC721Bridge},
+		{"L1StandardBridge", addrs.L1StandardBridgeProxy, deployed.L1StandardBridge, impls.L1StandardBridge, targets.L1StandardBridge},
+		{"L2OutputOracle", addrs.L2OutputOracleProxy, deployed.L2OutputOracle, impls.L2OutputOracle, targets.L2OutputOracle},
+		{"OptimismMintableERC20Factory", addrs.OptimismMintableERC20FactoryProxy, deployed.OptimismMintableERC20Factory, impls.OptimismMintableERC20Factory, targets.OptimismMintableERC20Factory},
+		{"OptimismPortal", addrs.OptimismPortalProxy, deployed.OptimismPortal, impls.OptimismPortal, targets.OptimismPortal},
+		{"SystemConfig", addrs.SystemConfigProxy, deployed.SystemConfig, impls.SystemConfig, targets.SystemConfig},
+	}
+
+	plan := &UpgradePlan{ChainID: chainID}
+	for _, e := range entries {
+		version := canonicalizeSemver(e.deployed)
+		if !semver.IsValid(version) {
+			return nil, fmt.Errorf("invalid deployed version %q of %s", e.deployed, e.contract)
+		}
+		step := UpgradeStep{
+			Contract: e.contract,
+			Proxy:    e.proxy,
+			Current:  VersionedContract{Version: version, Address: e.set.Get(version)},
+			Target:   e.target,
+		}
+		switch res := semver.Compare(version, e.target.Version); {
+		case res == 0:
+			step.Action = UpgradeNoop
+		case res < 0:
+			step.Action = UpgradeUpgrade
+		default:
+			step.Action = UpgradeDowngrade
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan, nil
+}
@@ -0,0 +1,79 @@
+package superchain
+
+import "testing"
+
+func TestNewUpgradePlan(t *testing.T) {
+	deployed := ContractVersions{
+		L1CrossDomainMessenger:       "1.6.0",
+		L1ERC721Bridge:               "1.4.0",
+		L1StandardBridge:             "1.3.0",
+		L2OutputOracle:               "1.6.0",
+		OptimismMintableERC20Factory: "1.6.0",
+		OptimismPortal:               "1.11.0",
+		SystemConfig:                 "1.10.0",
+	}
+	plan, err := NewUpgradePlan(10, deployed)
+	if err != nil {
+		t.Fatalf("failed to create upgrade plan: %v", err)
+	}
+	if !plan.NeedsUpgrade() {
+		t.Fatal("expected upgrades in plan")
+	}
+	expected := []struct {
+		contract string
+		action   UpgradeAction
+	}{
+		{"L1CrossDomainMessenger", UpgradeUpgrade},
+		{"L1ERC721Bridge", UpgradeNoop},
+		{"L1StandardBridge", UpgradeUpgrade},
+		{"L2OutputOracle", UpgradeNoop},
+		{"OptimismMintableERC20Factory", UpgradeNoop},
+		{"OptimismPortal", UpgradeDowngrade},
+		{"SystemConfig", UpgradeNoop},
+	}
+	if len(plan.Steps) != len(expected) {
+		t.Fatalf("expected %d steps, got %d", len(expected), len(plan.Steps))
+	}
+	for i, exp := range expected {
+		step := plan.Steps[i]
+		if step.Contract != exp.contract || step.Action != exp.action {
+			t.Errorf("step %d: expected %s %s, got %s %s", i, exp.contract, exp.action, step.Contract, step.Action)
+		}
+	}
+
+	messenger := plan.Steps[0]
+	if messenger.Proxy != Addresses[10].L1CrossDomainMessengerProxy {
+		t.Errorf("wrong proxy address %s", messenger.Proxy)
+	}
+	if messenger.Current.Address != HexToAddress("0xf4d5682dA3ad1820ea83E1cEE5Fd92a3A7BabC30") {
+		t.Errorf("wrong current implementation %s", messenger.Current.Address)
+	}
+	if messenger.Target.Version != "v1.7.0" || messenger.Target.Address != HexToAddress("0xDa2332D0a7608919Cd331B1304Cd179129a90495") {
+		t.Errorf("wrong target implementation %v", messenger.Target)
+	}
+	if plan.Steps[6].Proxy != OPChains[10].SystemConfigAddr {
+		t.Errorf("wrong SystemConfig proxy address %s", plan.Steps[6].Proxy)
+	}
+	// OptimismPortal 1.11.0 is not a known implementation
+	if plan.Steps[5].Current.Address != (Address{}) {
+		t.Errorf("expected unknown current OptimismPortal implementation, got %s", plan.Steps[5].Current.Address)
+	}
+}
+
+func TestNewUpgradePlanErrors(t *testing.T) {
+	if _, err := NewUpgradePlan(0, SuperchainSemver); err == nil {
+		t.Fatal("expected error for unknown chain")
+	}
+	deployed := SuperchainSemver
+	deployed.SystemConfig = "^1.0.0"
+	if _, err := NewUpgradePlan(10, deployed); err == nil {
+		t.Fatal("expected error for deployed version constraint")
+	}
+	plan, err := NewUpgradePlan(10, SuperchainSemver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.NeedsUpgrade() {
+		t.Fatal("expected no upgrades when deployed versions match the target")
+	}
+}
@@ -1,11 +1,14 @@
 package superchain
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
 
-	"golang.org/x/crypto/sha3"
+	"github.com/ethereum-optimism/superchain-registry/superchain/internal/abi"
 )
 
 // Util-types for hex-encoding/decoding.
@@ -25,6 +28,8 @@ func (b Address) String() string {
 	return encodeHex(b[:])
 }
 
+// HexToAddress decodes a 0x-prefixed hex string of 20 bytes to an Address.
+// It returns the zero address if the string is not a valid address.
 func HexToAddress(s string) Address {
 	var a Address
 	_ = a.UnmarshalText([]byte(s))
@@ -73,6 +78,14 @@ func (b Hash) String() string {
 	return encodeHex(b[:])
 }
 
+// HexToHash decodes a 0x-prefixed hex string of 32 bytes to a Hash.
+// It returns the zero hash if the string is not a valid hash.
+func HexToHash(s string) Hash {
+	var h Hash
+	_ = h.UnmarshalText([]byte(s))
+	return h
+}
+
 type HexBytes []byte
 
 func (b *HexBytes) UnmarshalText(text []byte) error {
@@ -113,8 +126,68 @@ func (b *HexBig) UnmarshalText(text []byte) error {
 	return (*big.Int)(b).UnmarshalText(text)
 }
 
+// HexUint64 is a uint64 that is encoded as a hex quantity, like "0x1c9c380".
+// Decoding from JSON also accepts plain JSON numbers, for compatibility with legacy data.
+type HexUint64 uint64
+
+func (b HexUint64) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+func (b HexUint64) String() string {
+	return "0x" + strconv.FormatUint(uint64(b), 16)
+}
+
+func (b *HexUint64) UnmarshalText(text []byte) error {
+	if !has0xPrefix(text) {
+		return fmt.Errorf("expected 0x prefix, but got %q", string(text))
+	}
+	v, err := strconv.ParseUint(string(text[2:]), 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid hex quantity %q: %w", string(text), err)
+	}
+	*b = HexUint64(v)
+	return nil
+}
+
+func (b *HexUint64) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return b.UnmarshalText([]byte(s))
+	}
+	v, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid quantity %s: %w", string(data), err)
+	}
+	*b = HexUint64(v)
+	return nil
+}
+
+// unmarshalLegacyBytes decodes a JSON string of 0x-prefixed hex bytes,
+// or, for compatibility with legacy data, of base64 bytes, the encoding/json default for []byte.
+func unmarshalLegacyBytes(data []byte) (HexBytes, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if has0xPrefix([]byte(s)) {
+		var out HexBytes
+		err := out.UnmarshalText([]byte(s))
+		return out, err
+	}
+	var out []byte
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func keccak256(v []byte) Hash {
-	st := sha3.NewLegacyKeccak256()
-	st.Write(v)
-	return *(*[32]byte)(st.Sum(nil))
+	return abi.Keccak256(v)
 }
@@ -0,0 +1,324 @@
+// Package l1test provides an in-memory L1 chain and a fake JSON-RPC node serving it,
+// to test verifiers without network access.
+package l1test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+// Chain is the in-memory state of an L1 chain. The state is the same for every block.
+// It implements verify.L1Backend, and is safe for concurrent use.
+type Chain struct {
+	mu      sync.Mutex
+	chainID uint64
+	calls   map[superchain.Address]map[string][]byte
+	code    map[superchain.Address][]byte
+	storage map[superchain.Address]map[superchain.Hash]superchain.Hash
+	blocks  []Block
+}
+
+// Block is a block of the fake chain.
+type Block struct {
+	Hash       superchain.Hash
+	ParentHash superchain.Hash
+	Number     uint64
+	Time       uint64
+}
+
+// NewChain returns an empty chain with the given chain ID, and a genesis block at time 0.
+func NewChain(chainID uint64) *Chain {
+	c := &Chain{
+		chainID: chainID,
+		calls:   map[superchain.Address]map[string][]byte{},
+		code:    map[superchain.Address][]byte{},
+		storage: map[superchain.Address]map[superchain.Hash]superchain.Hash{},
+	}
+	c.AddBlock(0)
+	return c
+}
+
+// SetCall sets the return data of an eth_call of the given calldata to the contract.
+// Calls with nil return data revert.
+func (c *Chain) SetCall(contract superchain.Address, data []byte, ret []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls[contract] == nil {
+		c.calls[contract] = map[string][]byte{}
+	}
+	c.calls[contract][string(data)] = ret
+}
+
+// SetCode sets the code of the account.
+func (c *Chain) SetCode(account superchain.Address, code []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.code[account] = code
+}
+
+// SetStorage sets the value of a storage slot of the account.
+func (c *Chain) SetStorage(account superchain.Address, slot superchain.Hash, value superchain.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.storage[account] == nil {
+		c.storage[account] = map[superchain.Hash]superchain.Hash{}
+	}
+	c.storage[account][slot] = value
+}
+
+// AddBlock appends a block with the given timestamp to the chain.
+func (c *Chain) AddBlock(time uint64) Block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b := Block{Number: uint64(len(c.blocks)), Time: time}
+	if len(c.blocks) > 0 {
+		b.ParentHash = c.blocks[len(c.blocks)-1].Hash
+	}
+	// The hash is not a real block hash, but is unique per block.
+	var preimage [48]byte
+	copy(preimage[:32], b.ParentHash[:])
+	binary.BigEndian.PutUint64(preimage[32:], b.Number)
+	binary.BigEndian.PutUint64(preimage[40:], b.Time)
+	st := sha3.NewLegacyKeccak256()
+	st.Write(preimage[:])
+	copy(b.Hash[:], st.Sum(nil))
+	c.blocks = append(c.blocks, b)
+	return b
+}
+
+// BlockByNumber returns the block with the given number, or the latest block if nil.
+func (c *Chain) BlockByNumber(number *uint64) (Block, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if number == nil {
+		return c.blocks[len(c.blocks)-1], true
+	}
+	if *number >= uint64(len(c.blocks)) {
+		return Block{}, false
+	}
+	return c.blocks[*number], true
+}
+
+// CallContract returns the return data set with SetCall, or an error if the call reverts.
+func (c *Chain) CallContract(ctx context.Context, to superchain.Address, data []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ret := c.calls[to][string(data)]
+	if ret == nil {
+		return nil, fmt.Errorf("execution reverted")
+	}
+	return bytes.Clone(ret), nil
+}
+
+// CodeAt returns the code of the account.
+func (c *Chain) CodeAt(ctx context.Context, account superchain.Address) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return bytes.Clone(c.code[account]), nil
+}
+
+// StorageAt returns the value of a storage slot of the account.
+func (c *Chain) StorageAt(ctx context.Context, account superchain.Address, slot superchain.Hash) (superchain.Hash, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.storage[account][slot], nil
+}
+
+// Node is a fake JSON-RPC node that serves the state of a Chain over HTTP.
+// It supports batch requests, and can be made to fail requests to test retries.
+type Node struct {
+	*httptest.Server
+	Chain *Chain
+
+	mu       sync.Mutex
+	failures int
+	requests int
+}
+
+// NewNode starts a node serving the chain. The node must be closed after use.
+func NewNode(chain *Chain) *Node {
+	n := &Node{Chain: chain}
+	n.Server = httptest.NewServer(http.HandlerFunc(n.serveHTTP))
+	return n
+}
+
+// FailNext makes the node respond to the next count HTTP requests with a 503 status.
+func (n *Node) FailNext(count int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.failures = count
+}
+
+// Requests returns the number of HTTP requests the node received.
+func (n *Node) Requests() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.requests
+}
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (n *Node) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	n.requests++
+	fail := n.failures > 0
+	if fail {
+		n.failures--
+	}
+	n.mu.Unlock()
+	if fail {
+		http.Error(w, "injected failure", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var out any
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var reqs []request
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resps := make([]response, len(reqs))
+		for i, req := range reqs {
+			resps[i] = n.handle(r.Context(), req)
+		}
+		out = resps
+	} else {
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		out = n.handle(r.Context(), req)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (n *Node) handle(ctx context.Context, req request) response {
+	resp := response{Version: "2.0", ID: req.ID}
+	result, err := n.dispatch(ctx, req.Method, req.Params)
+	switch {
+	case err != nil:
+		resp.Error = err
+	case result == nil:
+		resp.Result = json.RawMessage("null")
+	default:
+		resp.Result = result
+	}
+	return resp
+}
+
+func (n *Node) dispatch(ctx context.Context, method string, params []json.RawMessage) (any, *responseError) {
+	invalidParams := func(err error) *responseError {
+		return &responseError{Code: -32602, Message: fmt.Sprintf("invalid params: %v", err)}
+	}
+	switch method {
+	case "eth_chainId":
+		return quantity(n.Chain.chainID), nil
+	case "eth_call":
+		var msg struct {
+			To   superchain.Address  `json:"to"`
+			Data superchain.HexBytes `json:"data"`
+		}
+		if err := decodeParams(params, &msg); err != nil {
+			return nil, invalidParams(err)
+		}
+		ret, err := n.Chain.CallContract(ctx, msg.To, msg.Data)
+		if err != nil {
+			return nil, &responseError{Code: 3, Message: err.Error()}
+		}
+		return superchain.HexBytes(ret), nil
+	case "eth_getCode":
+		var account superchain.Address
+		if err := decodeParams(params, &account); err != nil {
+			return nil, invalidParams(err)
+		}
+		code, _ := n.Chain.CodeAt(ctx, account)
+		return superchain.HexBytes(code), nil
+	case "eth_getStorageAt":
+		var account superchain.Address
+		var slot superchain.Hash
+		if err := decodeParams(params, &account, &slot); err != nil {
+			return nil, invalidParams(err)
+		}
+		value, _ := n.Chain.StorageAt(ctx, account, slot)
+		return value, nil
+	case "eth_getBlockByNumber":
+		var tag string
+		if err := decodeParams(params, &tag); err != nil {
+			return nil, invalidParams(err)
+		}
+		var number *uint64
+		if tag != "latest" {
+			v, err := strconv.ParseUint(strings.TrimPrefix(tag, "0x"), 16, 64)
+			if err != nil {
+				return nil, invalidParams(err)
+			}
+			number = &v
+		}
+		b, ok := n.Chain.BlockByNumber(number)
+		if !ok {
+			return nil, nil
+		}
+		return map[string]any{
+			"hash":       b.Hash,
+			"parentHash": b.ParentHash,
+			"number":     quantity(b.Number),
+			"timestamp":  quantity(b.Time),
+		}, nil
+	default:
+		return nil, &responseError{Code: -32601, Message: fmt.Sprintf("the method %s does not exist/is not available", method)}
+	}
+}
+
+// decodeParams decodes the leading params into the given pointers, ignoring any trailing params.
+func decodeParams(params []json.RawMessage, dest ...any) error {
+	if len(params) < len(dest) {
+		return fmt.Errorf("expected at least %d params, got %d", len(dest), len(params))
+	}
+	for i, d := range dest {
+		if err := json.Unmarshal(params[i], d); err != nil {
+			return fmt.Errorf("param %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func quantity(v uint64) string {
+	return "0x" + strconv.FormatUint(v, 16)
+}
@@ -0,0 +1,51 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+// ProtocolVersions are the protocol versions that a ProtocolVersions contract signals.
+type ProtocolVersions struct {
+	Recommended superchain.ProtocolVersion `json:"recommended"`
+	Required    superchain.ProtocolVersion `json:"required"`
+}
+
+// ReadProtocolVersions reads the current recommended and required protocol versions
+// from the ProtocolVersions contract at the given address.
+func ReadProtocolVersions(ctx context.Context, backend L1Backend, addr superchain.Address) (*ProtocolVersions, error) {
+	recommended, err := readProtocolVersion(ctx, backend, addr, "recommended()")
+	if err != nil {
+		return nil, err
+	}
+	required, err := readProtocolVersion(ctx, backend, addr, "required()")
+	if err != nil {
+		return nil, err
+	}
+	return &ProtocolVersions{Recommended: recommended, Required: required}, nil
+}
+
+// ReadSuperchainProtocolVersions is like ReadProtocolVersions,
+// but reads the ProtocolVersions contract of the superchain target.
+func ReadSuperchainProtocolVersions(ctx context.Context, backend L1Backend, config *superchain.SuperchainConfig) (*ProtocolVersions, error) {
+	if config.ProtocolVersionsAddr == nil {
+		return nil, errors.New("superchain target has no ProtocolVersions contract")
+	}
+	return ReadProtocolVersions(ctx, backend, *config.ProtocolVersionsAddr)
+}
+
+func readProtocolVersion(ctx context.Context, backend L1Backend, addr superchain.Address, signature string) (superchain.ProtocolVersion, error) {
+	var out superchain.ProtocolVersion
+	ret, err := backend.CallContract(ctx, addr, Selector(signature))
+	if err != nil {
+		return out, fmt.Errorf("failed to call ProtocolVersions(%s).%s: %w", addr, signature, err)
+	}
+	if len(ret) != 32 {
+		return out, fmt.Errorf("expected 32 byte protocol version from ProtocolVersions(%s).%s, got %d bytes", addr, signature, len(ret))
+	}
+	copy(out[:], ret)
+	return out, nil
+}
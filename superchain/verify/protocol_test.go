@@ -0,0 +1,40 @@
+package verify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+	"github.com/ethereum-optimism/superchain-registry/superchain/verify/l1test"
+)
+
+func TestReadProtocolVersions(t *testing.T) {
+	config := superchain.Superchains["mainnet"].Config
+	addr := *config.ProtocolVersionsAddr
+	recommended := superchain.ProtocolVersionV0{Major: 6}.Encode()
+	required := superchain.ProtocolVersionV0{Major: 5}.Encode()
+
+	l1 := l1test.NewChain(1)
+	l1.SetCall(addr, Selector("recommended()"), recommended[:])
+	l1.SetCall(addr, Selector("required()"), required[:])
+
+	versions, err := ReadSuperchainProtocolVersions(context.Background(), l1, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if versions.Recommended != recommended || versions.Required != required {
+		t.Fatalf("unexpected protocol versions %s, %s", versions.Recommended, versions.Required)
+	}
+	node := superchain.ProtocolVersionV0{Major: 5, Minor: 1}.Encode()
+	if support, err := node.CheckSupport(versions.Recommended, versions.Required); err != nil || support != superchain.UpgradeRecommended {
+		t.Fatalf("unexpected support %s: %v", support, err)
+	}
+
+	l1.SetCall(addr, Selector("required()"), nil)
+	if _, err := ReadProtocolVersions(context.Background(), l1, addr); err == nil {
+		t.Fatal("expected error for reverting call")
+	}
+	if _, err := ReadSuperchainProtocolVersions(context.Background(), l1, &superchain.SuperchainConfig{}); err == nil {
+		t.Fatal("expected error for superchain without ProtocolVersions contract")
+	}
+}
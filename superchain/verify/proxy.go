@@ -0,0 +1,190 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+	"github.com/ethereum-optimism/superchain-registry/superchain/internal/abi"
+)
+
+var (
+	// implementationSlot is the EIP-1967 implementation slot, bytes32(uint256(keccak256('eip1967.proxy.implementation')) - 1).
+	implementationSlot = superchain.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// adminSlot is the EIP-1967 admin slot, bytes32(uint256(keccak256('eip1967.proxy.admin')) - 1).
+	adminSlot = superchain.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+)
+
+// l1CrossDomainMessengerName is the name that the ResolvedDelegateProxy of the
+// L1CrossDomainMessenger resolves its implementation by in the AddressManager.
+const l1CrossDomainMessengerName = "OVM_L1CrossDomainMessenger"
+
+// l1CrossDomainMessengerProxy is the only proxy without EIP-1967 slots, its admin is not read.
+const l1CrossDomainMessengerProxy = "L1CrossDomainMessengerProxy"
+
+// ProxyImplementation is the implementation that a proxy points at on L1.
+type ProxyImplementation struct {
+	// Proxy is the name of the proxy in the AddressList, e.g. "OptimismPortalProxy".
+	Proxy string `json:"proxy"`
+	// Address is the address of the proxy.
+	Address superchain.Address `json:"address"`
+	// Contract is the name of the contract that the proxy should point at, e.g. "OptimismPortal".
+	Contract string `json:"contract"`
+	// Implementation is the implementation address read from L1.
+	Implementation superchain.Address `json:"implementation"`
+	// Version is the version of the implementation, if it is a known implementation of Contract.
+	Version string `json:"version,omitempty"`
+	// KnownAs is the name of the contract that the implementation is known as,
+	// if it is a known implementation of another contract than Contract.
+	KnownAs string `json:"knownAs,omitempty"`
+	// Admin is the admin read from the EIP-1967 admin slot. It is unset for the
+	// L1CrossDomainMessengerProxy, which is administered through the AddressManager.
+	Admin superchain.Address `json:"admin"`
+	// Err is set if the proxy could not be read from L1.
+	Err error `json:"-"`
+}
+
+// Known returns whether the proxy points at a known implementation of its contract.
+func (p *ProxyImplementation) Known() bool {
+	return p.Err == nil && p.Version != ""
+}
+
+func (p *ProxyImplementation) String() string {
+	switch {
+	case p.Err != nil:
+		return fmt.Sprintf("error: %s(%s): %v", p.Proxy, p.Address, p.Err)
+	case p.KnownAs != "":
+		return fmt.Sprintf("wrong contract: %s(%s) points at %s, which is a %s implementation",
+			p.Proxy, p.Address, p.Implementation, p.KnownAs)
+	case p.Version == "":
+		return fmt.Sprintf("unknown implementation: %s(%s) points at %s, which is not a known %s implementation",
+			p.Proxy, p.Address, p.Implementation, p.Contract)
+	default:
+		return fmt.Sprintf("ok: %s(%s) points at %s %s (%s)", p.Proxy, p.Address, p.Contract, p.Version, p.Implementation)
+	}
+}
+
+// ProxyReport lists the implementations of all proxies of a chain.
+type ProxyReport struct {
+	ChainID uint64 `json:"chainId"`
+	// ProxyAdmin is the expected admin of the proxies.
+	ProxyAdmin superchain.Address    `json:"proxyAdmin"`
+	Proxies    []ProxyImplementation `json:"proxies"`
+}
+
+// Err returns an error that combines all proxies that could not be read,
+// point at unknown implementations, or have a different admin than the ProxyAdmin.
+// An empty admin slot is an error for every proxy, except for the L1CrossDomainMessengerProxy,
+// whose admin is not read.
+func (r *ProxyReport) Err() error {
+	var errs []error
+	for i := range r.Proxies {
+		p := &r.Proxies[i]
+		if !p.Known() {
+			errs = append(errs, errors.New(p.String()))
+		} else if p.Proxy == l1CrossDomainMessengerProxy {
+			continue
+		} else if p.Admin == (superchain.Address{}) {
+			errs = append(errs, fmt.Errorf("no admin: %s(%s) has an empty admin slot, expected ProxyAdmin %s",
+				p.Proxy, p.Address, r.ProxyAdmin))
+		} else if p.Admin != r.ProxyAdmin {
+			errs = append(errs, fmt.Errorf("wrong admin: %s(%s) is administered by %s, expected ProxyAdmin %s",
+				p.Proxy, p.Address, p.Admin, r.ProxyAdmin))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("proxies of chain %d are invalid: %w", r.ChainID, errors.Join(errs...))
+}
+
+// CheckProxyImplementations reads the EIP-1967 implementation and admin slots of every proxy
+// of a chain, and maps the implementations back to the contract names and versions of impls,
+// which are the implementations of the superchain target of the chain.
+//
+// The L1CrossDomainMessengerProxy is a ResolvedDelegateProxy without EIP-1967 slots,
+// its implementation is resolved through the AddressManager instead.
+//
+// All proxies are read, and failures are collected in the report instead of stopping early.
+// The returned error is only set if the context is canceled.
+func CheckProxyImplementations(ctx context.Context, backend L1Backend, chainID uint64,
+	addrs *superchain.AddressList, impls superchain.ContractImplementations,
+) (*ProxyReport, error) {
+	report := &ProxyReport{ChainID: chainID, ProxyAdmin: addrs.ProxyAdmin}
+	proxies := []struct {
+		proxy    string
+		address  superchain.Address
+		contract string
+		set      superchain.AddressSet
+	}{
+		{l1CrossDomainMessengerProxy, addrs.L1CrossDomainMessengerProxy, "L1CrossDomainMessenger", impls.L1CrossDomainMessenger},
+		{"L1ERC721BridgeProxy", addrs.L1ERC721BridgeProxy, "L1ERC721Bridge", impls.L1ERC721Bridge},
+		{"L1StandardBridgeProxy", addrs.L1StandardBridgeProxy, "L1StandardBridge", impls.L1StandardBridge},
+		{"L2OutputOracleProxy", addrs.L2OutputOracleProxy, "L2OutputOracle", impls.L2OutputOracle},
+		{"OptimismMintableERC20FactoryProxy", addrs.OptimismMintableERC20FactoryProxy, "OptimismMintableERC20Factory", impls.OptimismMintableERC20Factory},
+		{"OptimismPortalProxy", addrs.OptimismPortalProxy, "OptimismPortal", impls.OptimismPortal},
+		{"SystemConfigProxy", addrs.SystemConfigProxy, "SystemConfig", impls.SystemConfig},
+	}
+	for _, p := range proxies {
+		entry := ProxyImplementation{Proxy: p.proxy, Address: p.address, Contract: p.contract}
+		if p.proxy == l1CrossDomainMessengerProxy {
+			entry.Implementation, entry.Err = resolvedImplementation(ctx, backend, addrs.AddressManager, l1CrossDomainMessengerName)
+		} else {
+			entry.Implementation, entry.Admin, entry.Err = eip1967Slots(ctx, backend, p.address)
+		}
+		if entry.Err == nil {
+			if version, ok := p.set.Version(entry.Implementation); ok {
+				entry.Version = version
+			} else if contract, _, ok := impls.Lookup(entry.Implementation); ok {
+				entry.KnownAs = contract
+			}
+		}
+		report.Proxies = append(report.Proxies, entry)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// eip1967Slots reads the implementation and admin of an EIP-1967 proxy.
+func eip1967Slots(ctx context.Context, backend L1Backend, proxy superchain.Address) (impl superchain.Address, admin superchain.Address, err error) {
+	implValue, err := backend.StorageAt(ctx, proxy, implementationSlot)
+	if err != nil {
+		return impl, admin, fmt.Errorf("failed to read implementation slot: %w", err)
+	}
+	if impl, err = decodeAddress(implValue[:]); err != nil {
+		return impl, admin, fmt.Errorf("invalid implementation slot: %w", err)
+	}
+	adminValue, err := backend.StorageAt(ctx, proxy, adminSlot)
+	if err != nil {
+		return impl, admin, fmt.Errorf("failed to read admin slot: %w", err)
+	}
+	if admin, err = decodeAddress(adminValue[:]); err != nil {
+		return impl, admin, fmt.Errorf("invalid admin slot: %w", err)
+	}
+	return impl, admin, nil
+}
+
+// resolvedImplementation resolves the implementation of a ResolvedDelegateProxy
+// with AddressManager.getAddress(name).
+func resolvedImplementation(ctx context.Context, backend L1Backend, addressManager superchain.Address, name string) (superchain.Address, error) {
+	ret, err := backend.CallContract(ctx, addressManager, EncodeGetAddressCalldata(name))
+	if err != nil {
+		return superchain.Address{}, fmt.Errorf("failed to resolve %s in AddressManager %s: %w", name, addressManager, err)
+	}
+	return decodeAddress(ret)
+}
+
+// EncodeGetAddressCalldata returns the ABI-encoded calldata of AddressManager.getAddress(name).
+func EncodeGetAddressCalldata(name string) []byte {
+	out := append([]byte{}, Selector("getAddress(string)")...)
+	out = append(out, abi.Uint(32)...) // offset of the dynamic string argument
+	out = append(out, abi.Uint(uint64(len(name)))...)
+	out = append(out, name...)
+	if rem := len(name) % 32; rem != 0 {
+		out = append(out, make([]byte, 32-rem)...)
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package verify
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+	"github.com/ethereum-optimism/superchain-registry/superchain/verify/l1test"
+)
+
+func addressWord(addr superchain.Address) superchain.Hash {
+	var h superchain.Hash
+	copy(h[12:], addr[:])
+	return h
+}
+
+// upgradedL1 returns a fake L1 where all proxies of the given addresses point at the given implementations.
+func upgradedL1(addrs *superchain.AddressList, impls superchain.ImplementationList) *l1test.Chain {
+	chain := l1test.NewChain(1)
+	ret := addressWord(impls.L1CrossDomainMessenger.Address)
+	chain.SetCall(addrs.AddressManager, EncodeGetAddressCalldata("OVM_L1CrossDomainMessenger"), ret[:])
+	for proxy, impl := range map[superchain.Address]superchain.Address{
+		addrs.L1ERC721BridgeProxy:               impls.L1ERC721Bridge.Address,
+		addrs.L1StandardBridgeProxy:             impls.L1StandardBridge.Address,
+		addrs.L2OutputOracleProxy:               impls.L2OutputOracle.Address,
+		addrs.OptimismMintableERC20FactoryProxy: impls.OptimismMintableERC20Factory.Address,
+		addrs.OptimismPortalProxy:               impls.OptimismPortal.Address,
+		addrs.SystemConfigProxy:                 impls.SystemConfig.Address,
+	} {
+		chain.SetStorage(proxy, implementationSlot, addressWord(impl))
+		chain.SetStorage(proxy, adminSlot, addressWord(addrs.ProxyAdmin))
+	}
+	return chain
+}
+
+func TestEncodeGetAddressCalldata(t *testing.T) {
+	expected := "bf40fac1" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000001a" +
+		"4f564d5f4c3143726f7373446f6d61696e4d657373656e676572000000000000"
+	if got := hex.EncodeToString(EncodeGetAddressCalldata("OVM_L1CrossDomainMessenger")); got != expected {
+		t.Fatalf("wrong getAddress calldata: %s", got)
+	}
+}
+
+func TestCheckProxyImplementations(t *testing.T) {
+	addrs := superchain.Addresses[10]
+	impls, err := superchain.ImplementationsFor("mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := impls.Resolve(superchain.SuperchainSemver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l1 := upgradedL1(addrs, list)
+
+	report, err := CheckProxyImplementations(context.Background(), l1, 10, addrs, impls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := report.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Proxies) != 7 {
+		t.Fatalf("expected 7 proxies, got %d", len(report.Proxies))
+	}
+	for _, p := range report.Proxies {
+		if p.Contract == "OptimismPortal" && p.Version != list.OptimismPortal.Version {
+			t.Fatalf("expected OptimismPortal version %s, got %s", list.OptimismPortal.Version, p.Version)
+		}
+	}
+
+	unknown := superchain.HexToAddress("0x0000000000000000000000000000000000000bad")
+	l1.SetStorage(addrs.OptimismPortalProxy, implementationSlot, addressWord(unknown))
+	l1.SetStorage(addrs.SystemConfigProxy, implementationSlot, addressWord(list.L2OutputOracle.Address))
+	l1.SetStorage(addrs.L2OutputOracleProxy, adminSlot, addressWord(unknown))
+	l1.SetStorage(addrs.L1StandardBridgeProxy, adminSlot, superchain.Hash{})
+	report, err = CheckProxyImplementations(context.Background(), l1, 10, addrs, impls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = report.Err()
+	if err == nil {
+		t.Fatal("expected drift to be flagged")
+	}
+	for _, msg := range []string{
+		"unknown implementation: OptimismPortalProxy",
+		"wrong contract: SystemConfigProxy",
+		"wrong admin: L2OutputOracleProxy",
+		"no admin: L1StandardBridgeProxy",
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("expected %q in error: %v", msg, err)
+		}
+	}
+}
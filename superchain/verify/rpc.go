@@ -0,0 +1,302 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+)
+
+// Client is a minimal JSON-RPC client of an L1 execution node.
+// It implements L1Backend, and only depends on the standard library.
+type Client struct {
+	// URL is the HTTP endpoint of the node.
+	URL string
+	// HTTPClient is used to send requests.
+	HTTPClient *http.Client
+	// MaxRetries is the number of times a request is retried after a transport error,
+	// or after a rate-limit or server error status. JSON-RPC errors are not retried.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry. It doubles for every next retry.
+	RetryBackoff time.Duration
+
+	nextID atomic.Uint64
+}
+
+var _ L1Backend = (*Client)(nil)
+
+// NewClient returns a Client of the given endpoint, with default retry settings.
+func NewClient(url string) *Client {
+	return &Client{
+		URL:          url,
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+		MaxRetries:   3,
+		RetryBackoff: 500 * time.Millisecond,
+	}
+}
+
+// NewL1Client returns a Client of the public RPC of the L1 chain of a superchain target.
+func NewL1Client(l1 superchain.SuperchainL1Info) (*Client, error) {
+	if l1.PublicRPC == "" {
+		return nil, fmt.Errorf("no public RPC known for L1 chain %d", l1.ChainID)
+	}
+	return NewClient(l1.PublicRPC), nil
+}
+
+// RPCError is an error returned by the node in a JSON-RPC response.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// BatchElem is a single request of a batch, see Client.BatchCall.
+type BatchElem struct {
+	Method string
+	Params []any
+	// Result is decoded into if the request succeeds, and must be a pointer.
+	Result any
+	// Error is set if the request failed.
+	Error error
+}
+
+type jsonrpcRequest struct {
+	Version string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type jsonrpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// Call sends a single request, and decodes the result into the given pointer.
+func (c *Client) Call(ctx context.Context, result any, method string, params ...any) error {
+	if params == nil {
+		params = []any{}
+	}
+	req := jsonrpcRequest{Version: "2.0", ID: c.nextID.Add(1), Method: method, Params: params}
+	var resp jsonrpcResponse
+	if err := c.send(ctx, req, &resp); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if resp.ID != req.ID {
+		return fmt.Errorf("%s: response id %d does not match request id %d", method, resp.ID, req.ID)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s: %w", method, resp.Error)
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("%s: failed to decode result: %w", method, err)
+	}
+	return nil
+}
+
+// BatchCall sends all requests in a single batch. The returned error is only set if the batch
+// as a whole failed, errors of individual requests are set in their BatchElem.
+func (c *Client) BatchCall(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	reqs := make([]jsonrpcRequest, len(batch))
+	byID := make(map[uint64]int, len(batch))
+	for i, elem := range batch {
+		params := elem.Params
+		if params == nil {
+			params = []any{}
+		}
+		reqs[i] = jsonrpcRequest{Version: "2.0", ID: c.nextID.Add(1), Method: elem.Method, Params: params}
+		byID[reqs[i].ID] = i
+	}
+	var resps []jsonrpcResponse
+	if err := c.send(ctx, reqs, &resps); err != nil {
+		return fmt.Errorf("batch: %w", err)
+	}
+	for i := range batch {
+		batch[i].Error = fmt.Errorf("%s: missing response", batch[i].Method)
+	}
+	for _, resp := range resps {
+		i, ok := byID[resp.ID]
+		if !ok {
+			return fmt.Errorf("batch: response with unknown id %d", resp.ID)
+		}
+		elem := &batch[i]
+		switch {
+		case resp.Error != nil:
+			elem.Error = fmt.Errorf("%s: %w", elem.Method, resp.Error)
+		case elem.Result == nil:
+			elem.Error = nil
+		default:
+			if err := json.Unmarshal(resp.Result, elem.Result); err != nil {
+				elem.Error = fmt.Errorf("%s: failed to decode result: %w", elem.Method, err)
+			} else {
+				elem.Error = nil
+			}
+		}
+	}
+	return nil
+}
+
+// retryableError is an error of a request that may succeed if it is sent again.
+type retryableError struct{ err error }
+
+func (e *retryableError) Error() string { return e.err.Error() }
+
+func (e *retryableError) Unwrap() error { return e.err }
+
+// send posts the request and decodes the response, retrying retryable failures.
+func (c *Client) send(ctx context.Context, req any, resp any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	backoff := c.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err = c.post(ctx, body, resp)
+		var retryable *retryableError
+		if err == nil || !errors.As(err, &retryable) || attempt >= c.MaxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) post(ctx context.Context, body []byte, resp any) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return &retryableError{err}
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return &retryableError{fmt.Errorf("failed to read response: %w", err)}
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("http status %s: %s", httpResp.Status, bytes.TrimSpace(data))
+		if httpResp.StatusCode == http.StatusTooManyRequests || httpResp.StatusCode >= 500 {
+			return &retryableError{err}
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// blockTag returns the JSON-RPC block parameter of the given block number, or "latest" if nil.
+func blockTag(number *uint64) string {
+	if number == nil {
+		return "latest"
+	}
+	return "0x" + strconv.FormatUint(*number, 16)
+}
+
+// ChainID returns the chain ID of the node, with eth_chainId.
+func (c *Client) ChainID(ctx context.Context) (uint64, error) {
+	var id superchain.HexUint64
+	if err := c.Call(ctx, &id, "eth_chainId"); err != nil {
+		return 0, err
+	}
+	return uint64(id), nil
+}
+
+type callMsg struct {
+	To   superchain.Address  `json:"to"`
+	Data superchain.HexBytes `json:"data"`
+}
+
+// CallContract executes an eth_call on the latest block, from the zero address.
+func (c *Client) CallContract(ctx context.Context, to superchain.Address, data []byte) ([]byte, error) {
+	var out superchain.HexBytes
+	if err := c.Call(ctx, &out, "eth_call", callMsg{To: to, Data: data}, "latest"); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CodeAt returns the code of the account on the latest block, with eth_getCode.
+func (c *Client) CodeAt(ctx context.Context, account superchain.Address) ([]byte, error) {
+	var out superchain.HexBytes
+	if err := c.Call(ctx, &out, "eth_getCode", account, "latest"); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StorageAt returns the value of the storage slot of the account on the latest block, with eth_getStorageAt.
+func (c *Client) StorageAt(ctx context.Context, account superchain.Address, slot superchain.Hash) (superchain.Hash, error) {
+	var out superchain.Hash
+	if err := c.Call(ctx, &out, "eth_getStorageAt", account, slot, "latest"); err != nil {
+		return superchain.Hash{}, err
+	}
+	return out, nil
+}
+
+// BlockHeader is the subset of the block fields that the verifiers use.
+type BlockHeader struct {
+	Hash       superchain.Hash
+	ParentHash superchain.Hash
+	Number     uint64
+	Time       uint64
+}
+
+func (h *BlockHeader) UnmarshalJSON(data []byte) error {
+	var dec struct {
+		Hash       superchain.Hash      `json:"hash"`
+		ParentHash superchain.Hash      `json:"parentHash"`
+		Number     superchain.HexUint64 `json:"number"`
+		Time       superchain.HexUint64 `json:"timestamp"`
+	}
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	*h = BlockHeader{Hash: dec.Hash, ParentHash: dec.ParentHash, Number: uint64(dec.Number), Time: uint64(dec.Time)}
+	return nil
+}
+
+// BlockByNumber returns the header of the block with the given number, or of the latest block if nil,
+// with eth_getBlockByNumber.
+func (c *Client) BlockByNumber(ctx context.Context, number *uint64) (*BlockHeader, error) {
+	var out *BlockHeader
+	if err := c.Call(ctx, &out, "eth_getBlockByNumber", blockTag(number), false); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, fmt.Errorf("block %s not found", blockTag(number))
+	}
+	return out, nil
+}
@@ -0,0 +1,158 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+	"github.com/ethereum-optimism/superchain-registry/superchain/verify/l1test"
+)
+
+func newTestClient(t *testing.T, chain *l1test.Chain) (*Client, *l1test.Node) {
+	node := l1test.NewNode(chain)
+	t.Cleanup(node.Close)
+	client := NewClient(node.URL)
+	client.RetryBackoff = time.Millisecond
+	return client, node
+}
+
+func TestClient(t *testing.T) {
+	chain := l1test.NewChain(11155111)
+	contract := superchain.HexToAddress("0x0000000000000000000000000000000000001234")
+	chain.SetCode(contract, []byte{0x60, 0x00})
+	chain.SetStorage(contract, superchain.Hash{31: 1}, superchain.Hash{31: 0x42})
+	chain.SetCall(contract, Selector("owner()"), bytes.Repeat([]byte{0x11}, 32))
+	chain.AddBlock(12)
+	client, _ := newTestClient(t, chain)
+	ctx := context.Background()
+
+	if id, err := client.ChainID(ctx); err != nil || id != 11155111 {
+		t.Fatalf("wrong chain ID %d: %v", id, err)
+	}
+	if code, err := client.CodeAt(ctx, contract); err != nil || !bytes.Equal(code, []byte{0x60, 0x00}) {
+		t.Fatalf("wrong code %x: %v", code, err)
+	}
+	if value, err := client.StorageAt(ctx, contract, superchain.Hash{31: 1}); err != nil || value != (superchain.Hash{31: 0x42}) {
+		t.Fatalf("wrong storage %s: %v", value, err)
+	}
+	if ret, err := client.CallContract(ctx, contract, Selector("owner()")); err != nil || !bytes.Equal(ret, bytes.Repeat([]byte{0x11}, 32)) {
+		t.Fatalf("wrong call result %x: %v", ret, err)
+	}
+	_, err := client.CallContract(ctx, contract, Selector("admin()"))
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected rpc error of reverted call, got %v", err)
+	}
+
+	latest, err := client.BlockByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Number != 1 || latest.Time != 12 {
+		t.Fatalf("wrong latest block %+v", latest)
+	}
+	genesisNum := uint64(0)
+	genesis, err := client.BlockByNumber(ctx, &genesisNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.ParentHash != genesis.Hash {
+		t.Fatalf("latest block parent %s is not genesis %s", latest.ParentHash, genesis.Hash)
+	}
+	missingNum := uint64(100)
+	if _, err := client.BlockByNumber(ctx, &missingNum); err == nil {
+		t.Fatal("expected error for missing block")
+	}
+}
+
+func TestClientBatchCall(t *testing.T) {
+	chain := l1test.NewChain(1)
+	contract := superchain.HexToAddress("0x0000000000000000000000000000000000001234")
+	chain.SetStorage(contract, superchain.Hash{}, superchain.Hash{31: 7})
+	client, node := newTestClient(t, chain)
+
+	var chainID string
+	var value superchain.Hash
+	batch := []BatchElem{
+		{Method: "eth_chainId", Result: &chainID},
+		{Method: "eth_getStorageAt", Params: []any{contract, superchain.Hash{}, "latest"}, Result: &value},
+		{Method: "eth_unknown"},
+	}
+	if err := client.BatchCall(context.Background(), batch); err != nil {
+		t.Fatal(err)
+	}
+	if node.Requests() != 1 {
+		t.Fatalf("expected a single HTTP request, got %d", node.Requests())
+	}
+	if batch[0].Error != nil || chainID != "0x1" {
+		t.Fatalf("wrong chain ID %s: %v", chainID, batch[0].Error)
+	}
+	if batch[1].Error != nil || value != (superchain.Hash{31: 7}) {
+		t.Fatalf("wrong storage %s: %v", value, batch[1].Error)
+	}
+	if batch[2].Error == nil {
+		t.Fatal("expected error of unknown method")
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	client, node := newTestClient(t, l1test.NewChain(1))
+	client.MaxRetries = 2
+
+	node.FailNext(2)
+	if _, err := client.ChainID(context.Background()); err != nil {
+		t.Fatalf("expected request to succeed after retries: %v", err)
+	}
+	if node.Requests() != 3 {
+		t.Fatalf("expected 3 requests, got %d", node.Requests())
+	}
+
+	node.FailNext(3)
+	if _, err := client.ChainID(context.Background()); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if node.Requests() != 6 {
+		t.Fatalf("expected 6 requests, got %d", node.Requests())
+	}
+}
+
+func TestClientResponseID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1000,"result":"0x1"}`))
+	}))
+	t.Cleanup(srv.Close)
+	_, err := NewClient(srv.URL).ChainID(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "response id 1000 does not match request id 1") {
+		t.Fatalf("expected id mismatch error, got %v", err)
+	}
+}
+
+func TestClientCheckSecurityConfigs(t *testing.T) {
+	addrs := superchain.Addresses[10]
+	client, _ := newTestClient(t, secureL1(addrs))
+	report, err := CheckSecurityConfigs(context.Background(), client, 10, addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := report.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewL1Client(t *testing.T) {
+	for name, sc := range superchain.Superchains {
+		if _, err := NewL1Client(sc.Config.L1); err != nil {
+			t.Errorf("superchain %s: %v", name, err)
+		}
+	}
+	if _, err := NewL1Client(superchain.SuperchainL1Info{ChainID: 1}); err == nil {
+		t.Fatal("expected error without public RPC")
+	}
+}
@@ -0,0 +1,216 @@
+// Package verify checks registry data against the state of the L1 chain.
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+	"github.com/ethereum-optimism/superchain-registry/superchain/internal/abi"
+)
+
+// L1Backend is the read-only access to L1 state that the verifiers need.
+// It can be implemented by an RPC client, or by an in-memory fake for testing.
+type L1Backend interface {
+	// CallContract executes an eth_call of the given calldata on the latest block.
+	// Calls are made from the zero address, so proxies answer admin() instead of delegating it.
+	CallContract(ctx context.Context, to superchain.Address, data []byte) ([]byte, error)
+	// StorageAt returns the value of the storage slot of the given account on the latest block,
+	// like eth_getStorageAt.
+	StorageAt(ctx context.Context, account superchain.Address, slot superchain.Hash) (superchain.Hash, error)
+}
+
+// finalizationPeriod is the expected L2OutputOracle finalization period of 7 days (604800 seconds).
+// Like the script, the period is compared as an address-sized word.
+var finalizationPeriod = superchain.HexToAddress("0x0000000000000000000000000000000000093a80")
+
+// addressManagerSlot is the storage slot of the addressManager mapping of the
+// ResolvedDelegateProxy that the L1CrossDomainMessengerProxy is.
+const addressManagerSlot = 1
+
+// SecurityCheck is the result of checking a single address of a contract.
+type SecurityCheck struct {
+	// Contract is the name of the checked contract in the AddressList, e.g. "OptimismPortalProxy".
+	Contract string `json:"contract"`
+	// Address is the address of the checked contract.
+	Address superchain.Address `json:"address"`
+	// Method is the signature of the called method, e.g. "GUARDIAN()",
+	// or a description of the read storage slot.
+	Method string `json:"method"`
+	// Expected is the address that the registry data expects.
+	Expected superchain.Address `json:"expected"`
+	// Actual is the address read from L1. It is unset if the check was skipped or errored.
+	Actual superchain.Address `json:"actual"`
+	// Skipped is set if the expected address is not known for the chain.
+	Skipped bool `json:"skipped,omitempty"`
+	// Err is set if the address could not be read from L1.
+	Err error `json:"-"`
+}
+
+// OK returns whether the check passed or was skipped.
+func (c *SecurityCheck) OK() bool {
+	return c.Skipped || (c.Err == nil && c.Expected == c.Actual)
+}
+
+func (c *SecurityCheck) String() string {
+	switch {
+	case c.Skipped:
+		return fmt.Sprintf("skipped: %s.%s, expected address is unknown", c.Contract, c.Method)
+	case c.Err != nil:
+		return fmt.Sprintf("error: %s(%s).%s: %v", c.Contract, c.Address, c.Method, c.Err)
+	case c.Expected != c.Actual:
+		return fmt.Sprintf("mismatch: %s(%s).%s is %s, expected %s", c.Contract, c.Address, c.Method, c.Actual, c.Expected)
+	default:
+		return fmt.Sprintf("ok: %s(%s).%s is %s", c.Contract, c.Address, c.Method, c.Actual)
+	}
+}
+
+// SecurityReport is the list of all checks that were performed for a chain.
+type SecurityReport struct {
+	ChainID uint64          `json:"chainId"`
+	Checks  []SecurityCheck `json:"checks"`
+}
+
+// Err returns an error that combines all failed checks, or nil if all checks passed.
+func (r *SecurityReport) Err() error {
+	var errs []error
+	for i := range r.Checks {
+		if c := &r.Checks[i]; !c.OK() {
+			errs = append(errs, errors.New(c.String()))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("security configs of chain %d are invalid: %w", r.ChainID, errors.Join(errs...))
+}
+
+// CheckSecurityConfigs checks the ownership and wiring of the L1 contracts of a chain,
+// like scripts/CheckSecurityConfigs.s.sol does:
+// the ProxyAdmin owns the proxies and the AddressManager, the privileged roles
+// are assigned to the accounts of the AddressList, and the proxies point at each other.
+//
+// All checks are performed, and failures are collected in the report instead of stopping early.
+// Checks of privileged roles that are not known for the chain are skipped.
+// The returned error is only set if the context is canceled.
+func CheckSecurityConfigs(ctx context.Context, backend L1Backend, chainID uint64, addrs *superchain.AddressList) (*SecurityReport, error) {
+	s := &securityChecker{backend: backend, report: &SecurityReport{ChainID: chainID}}
+
+	// AddressManager
+	s.call(ctx, "AddressManager", addrs.AddressManager, "owner()", addrs.ProxyAdmin)
+
+	// L1CrossDomainMessengerProxy
+	s.mappingValue(ctx, "L1CrossDomainMessengerProxy", addrs.L1CrossDomainMessengerProxy,
+		addressManagerSlot, addrs.L1CrossDomainMessengerProxy, addrs.AddressManager)
+	s.call(ctx, "L1CrossDomainMessengerProxy", addrs.L1CrossDomainMessengerProxy, "PORTAL()", addrs.OptimismPortalProxy)
+
+	// L1ERC721BridgeProxy
+	s.call(ctx, "L1ERC721BridgeProxy", addrs.L1ERC721BridgeProxy, "admin()", addrs.ProxyAdmin)
+	s.call(ctx, "L1ERC721BridgeProxy", addrs.L1ERC721BridgeProxy, "messenger()", addrs.L1CrossDomainMessengerProxy)
+
+	// L1StandardBridgeProxy, a L1ChugSplashProxy that exposes its admin through getOwner()
+	s.call(ctx, "L1StandardBridgeProxy", addrs.L1StandardBridgeProxy, "getOwner()", addrs.ProxyAdmin)
+	s.call(ctx, "L1StandardBridgeProxy", addrs.L1StandardBridgeProxy, "messenger()", addrs.L1CrossDomainMessengerProxy)
+
+	// L2OutputOracleProxy
+	s.call(ctx, "L2OutputOracleProxy", addrs.L2OutputOracleProxy, "admin()", addrs.ProxyAdmin)
+	s.call(ctx, "L2OutputOracleProxy", addrs.L2OutputOracleProxy, "CHALLENGER()", addrs.Challenger)
+	s.call(ctx, "L2OutputOracleProxy", addrs.L2OutputOracleProxy, "FINALIZATION_PERIOD_SECONDS()", finalizationPeriod)
+
+	// OptimismMintableERC20FactoryProxy
+	s.call(ctx, "OptimismMintableERC20FactoryProxy", addrs.OptimismMintableERC20FactoryProxy, "admin()", addrs.ProxyAdmin)
+	s.call(ctx, "OptimismMintableERC20FactoryProxy", addrs.OptimismMintableERC20FactoryProxy, "BRIDGE()", addrs.L1StandardBridgeProxy)
+
+	// OptimismPortalProxy
+	s.call(ctx, "OptimismPortalProxy", addrs.OptimismPortalProxy, "admin()", addrs.ProxyAdmin)
+	s.call(ctx, "OptimismPortalProxy", addrs.OptimismPortalProxy, "GUARDIAN()", addrs.Guardian)
+	s.call(ctx, "OptimismPortalProxy", addrs.OptimismPortalProxy, "L2_ORACLE()", addrs.L2OutputOracleProxy)
+	s.call(ctx, "OptimismPortalProxy", addrs.OptimismPortalProxy, "SYSTEM_CONFIG()", addrs.SystemConfigProxy)
+
+	// ProxyAdmin
+	s.call(ctx, "ProxyAdmin", addrs.ProxyAdmin, "owner()", addrs.ProxyAdminOwner)
+	s.call(ctx, "ProxyAdmin", addrs.ProxyAdmin, "addressManager()", addrs.AddressManager)
+
+	// SystemConfigProxy
+	s.call(ctx, "SystemConfigProxy", addrs.SystemConfigProxy, "admin()", addrs.ProxyAdmin)
+	s.call(ctx, "SystemConfigProxy", addrs.SystemConfigProxy, "owner()", addrs.SystemConfigOwner)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.report, nil
+}
+
+type securityChecker struct {
+	backend L1Backend
+	report  *SecurityReport
+}
+
+// call checks that the method with the given signature returns the expected address.
+func (s *securityChecker) call(ctx context.Context, contract string, addr superchain.Address, signature string, expected superchain.Address) {
+	check := SecurityCheck{Contract: contract, Address: addr, Method: signature, Expected: expected}
+	if expected == (superchain.Address{}) {
+		check.Skipped = true
+	} else if ret, err := s.backend.CallContract(ctx, addr, Selector(signature)); err != nil {
+		check.Err = err
+	} else {
+		check.Actual, check.Err = decodeAddress(ret)
+	}
+	s.report.Checks = append(s.report.Checks, check)
+}
+
+// mappingValue checks that the value of the mapping at the given storage slot,
+// for the given address key, is the expected address.
+func (s *securityChecker) mappingValue(ctx context.Context, contract string, addr superchain.Address,
+	mapSlot uint64, key superchain.Address, expected superchain.Address,
+) {
+	check := SecurityCheck{
+		Contract: contract,
+		Address:  addr,
+		Method:   fmt.Sprintf("storage mapping at slot %d", mapSlot),
+		Expected: expected,
+	}
+	if expected == (superchain.Address{}) {
+		check.Skipped = true
+	} else if value, err := s.backend.StorageAt(ctx, addr, MappingSlot(mapSlot, key)); err != nil {
+		check.Err = err
+	} else {
+		check.Actual, check.Err = decodeAddress(value[:])
+	}
+	s.report.Checks = append(s.report.Checks, check)
+}
+
+// Selector returns the 4 byte ABI selector of the method with the given signature, e.g. "owner()".
+func Selector(signature string) []byte {
+	h := keccak256([]byte(signature))
+	return h[:4]
+}
+
+// MappingSlot returns the storage slot of the value of an address-keyed mapping
+// at the given slot, i.e. keccak256(abi.encode(key, mapSlot)).
+func MappingSlot(mapSlot uint64, key superchain.Address) superchain.Hash {
+	var preimage [64]byte
+	copy(preimage[12:32], key[:])
+	copy(preimage[32:], abi.Uint(mapSlot))
+	return keccak256(preimage[:])
+}
+
+// decodeAddress decodes an ABI-encoded address return value.
+func decodeAddress(ret []byte) (superchain.Address, error) {
+	var addr superchain.Address
+	if len(ret) != 32 {
+		return addr, fmt.Errorf("expected 32 byte address word, got %d bytes", len(ret))
+	}
+	for _, b := range ret[:12] {
+		if b != 0 {
+			return addr, fmt.Errorf("invalid address word %x", ret)
+		}
+	}
+	copy(addr[:], ret[12:])
+	return addr, nil
+}
+
+func keccak256(v []byte) superchain.Hash {
+	return abi.Keccak256(v)
+}
@@ -0,0 +1,132 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+	"github.com/ethereum-optimism/superchain-registry/superchain/verify/l1test"
+)
+
+// setAddress sets the return value of the method with the given signature to the address.
+func setAddress(l1 *l1test.Chain, contract superchain.Address, signature string, value superchain.Address) {
+	ret := make([]byte, 32)
+	copy(ret[12:], value[:])
+	l1.SetCall(contract, Selector(signature), ret)
+}
+
+// secureL1 returns a fake L1 with the state of correctly configured contracts of the given addresses.
+func secureL1(addrs *superchain.AddressList) *l1test.Chain {
+	f := l1test.NewChain(1)
+	setAddress(f, addrs.AddressManager, "owner()", addrs.ProxyAdmin)
+	var addressManager superchain.Hash
+	copy(addressManager[12:], addrs.AddressManager[:])
+	f.SetStorage(addrs.L1CrossDomainMessengerProxy, MappingSlot(1, addrs.L1CrossDomainMessengerProxy), addressManager)
+	setAddress(f, addrs.L1CrossDomainMessengerProxy, "PORTAL()", addrs.OptimismPortalProxy)
+	setAddress(f, addrs.L1ERC721BridgeProxy, "admin()", addrs.ProxyAdmin)
+	setAddress(f, addrs.L1ERC721BridgeProxy, "messenger()", addrs.L1CrossDomainMessengerProxy)
+	setAddress(f, addrs.L1StandardBridgeProxy, "getOwner()", addrs.ProxyAdmin)
+	setAddress(f, addrs.L1StandardBridgeProxy, "messenger()", addrs.L1CrossDomainMessengerProxy)
+	setAddress(f, addrs.L2OutputOracleProxy, "admin()", addrs.ProxyAdmin)
+	setAddress(f, addrs.L2OutputOracleProxy, "CHALLENGER()", addrs.Challenger)
+	setAddress(f, addrs.L2OutputOracleProxy, "FINALIZATION_PERIOD_SECONDS()", finalizationPeriod)
+	setAddress(f, addrs.OptimismMintableERC20FactoryProxy, "admin()", addrs.ProxyAdmin)
+	setAddress(f, addrs.OptimismMintableERC20FactoryProxy, "BRIDGE()", addrs.L1StandardBridgeProxy)
+	setAddress(f, addrs.OptimismPortalProxy, "admin()", addrs.ProxyAdmin)
+	setAddress(f, addrs.OptimismPortalProxy, "GUARDIAN()", addrs.Guardian)
+	setAddress(f, addrs.OptimismPortalProxy, "L2_ORACLE()", addrs.L2OutputOracleProxy)
+	setAddress(f, addrs.OptimismPortalProxy, "SYSTEM_CONFIG()", addrs.SystemConfigProxy)
+	setAddress(f, addrs.ProxyAdmin, "owner()", addrs.ProxyAdminOwner)
+	setAddress(f, addrs.ProxyAdmin, "addressManager()", addrs.AddressManager)
+	setAddress(f, addrs.SystemConfigProxy, "admin()", addrs.ProxyAdmin)
+	setAddress(f, addrs.SystemConfigProxy, "owner()", addrs.SystemConfigOwner)
+	return f
+}
+
+func TestSelector(t *testing.T) {
+	if got := fmt.Sprintf("%x", Selector("owner()")); got != "8da5cb5b" {
+		t.Fatalf("wrong owner() selector %s", got)
+	}
+}
+
+func TestCheckSecurityConfigs(t *testing.T) {
+	for chainID, addrs := range superchain.Addresses {
+		if superchain.OPChains[chainID].Superchain != "mainnet" {
+			continue
+		}
+		report, err := CheckSecurityConfigs(context.Background(), secureL1(addrs), chainID, addrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := report.Err(); err != nil {
+			t.Errorf("chain %d: %v", chainID, err)
+		}
+		if len(report.Checks) != 20 {
+			t.Errorf("chain %d: expected 20 checks, got %d", chainID, len(report.Checks))
+		}
+	}
+}
+
+func TestCheckSecurityConfigsFailures(t *testing.T) {
+	addrs := superchain.Addresses[10]
+	l1 := secureL1(addrs)
+	attacker := superchain.HexToAddress("0x0000000000000000000000000000000000000bad")
+	setAddress(l1, addrs.OptimismPortalProxy, "GUARDIAN()", attacker)
+	l1.SetStorage(addrs.L1CrossDomainMessengerProxy, MappingSlot(1, addrs.L1CrossDomainMessengerProxy), superchain.Hash{})
+	l1.SetCall(addrs.ProxyAdmin, Selector("owner()"), nil)
+
+	report, err := CheckSecurityConfigs(context.Background(), l1, 10, addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failed []string
+	for _, c := range report.Checks {
+		if !c.OK() {
+			failed = append(failed, c.Contract+"."+c.Method)
+		}
+	}
+	expected := []string{
+		"L1CrossDomainMessengerProxy.storage mapping at slot 1",
+		"OptimismPortalProxy.GUARDIAN()",
+		"ProxyAdmin.owner()",
+	}
+	if strings.Join(failed, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected failures %v, got %v", expected, failed)
+	}
+	if err := report.Err(); err == nil || !strings.Contains(err.Error(), attacker.String()) {
+		t.Fatalf("expected error to name the wrong guardian, got %v", err)
+	}
+}
+
+func TestCheckSecurityConfigsUnknownRoles(t *testing.T) {
+	addrs := *superchain.Addresses[10]
+	addrs.PrivilegedRoles = superchain.PrivilegedRoles{}
+	report, err := CheckSecurityConfigs(context.Background(), secureL1(&addrs), 10, &addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := report.Err(); err != nil {
+		t.Fatalf("unknown roles should be skipped: %v", err)
+	}
+	skipped := 0
+	for _, c := range report.Checks {
+		if c.Skipped {
+			skipped++
+		}
+	}
+	if skipped != 4 {
+		t.Fatalf("expected 4 skipped role checks, got %d", skipped)
+	}
+}
+
+func TestCheckSecurityConfigsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := CheckSecurityConfigs(ctx, l1test.NewChain(1), 10, superchain.Addresses[10])
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled error, got %v", err)
+	}
+}